@@ -4,8 +4,11 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"smm/internal/backup"
 	"smm/internal/bootstrap"
 	"smm/internal/config"
+	"smm/internal/doctor"
+	"smm/internal/export"
 	"smm/internal/model"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,11 +16,6 @@ import (
 
 var version = "dev"
 
-func init() {
-	bootstrap.LoadConfig()
-	bootstrap.SetLog()
-}
-
 func main() {
 	projectIdFlag := flag.String("p", "", "Project ID to use")
 	versionFlag := flag.Bool("v", false, "Show version and exit")
@@ -28,12 +26,28 @@ func main() {
 		os.Exit(0)
 	}
 
-	projectId := *projectIdFlag
-	if projectId == "" {
-		projectId = config.GetSelectedProjectId()
+	// doctor checks the config and log path itself instead of failing on
+	// them.
+	bootstrap.DisableLog()
+	if flag.Arg(0) == "doctor" {
+		os.Exit(doctor.Main(os.Stdout))
+	}
+	if flag.Arg(0) == "export" {
+		os.Exit(export.Main(flag.Args()[1:], *projectIdFlag, os.Stdout, os.Stderr))
+	}
+	if flag.Arg(0) == "backup" {
+		os.Exit(backup.Main(flag.Args()[1:], *projectIdFlag, os.Stdout, os.Stderr))
 	}
+	if flag.Arg(0) == "restore" {
+		os.Exit(backup.RestoreMain(flag.Args()[1:], *projectIdFlag, os.Stdout, os.Stderr))
+	}
+
+	bootstrap.LoadConfig()
+	bootstrap.SetLog()
+
+	projectId := config.ResolveProjectId(*projectIdFlag)
 
-	p := tea.NewProgram(model.New(projectId), tea.WithAltScreen())
+	p := tea.NewProgram(model.New(projectId), tea.WithAltScreen(), tea.WithReportFocus())
 
 	_, err := p.Run()
 	if err != nil {
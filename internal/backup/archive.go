@@ -0,0 +1,165 @@
+// Package backup snapshots every secret of a project, with its metadata and
+// enabled versions, into an age-encrypted archive and restores it into the
+// same or another project through client.Client.
+package backup
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"smm/internal/client"
+	"strconv"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+)
+
+// formatVersion is the version of the archive layout, checked on restore.
+const formatVersion = 1
+
+// Archive is the decrypted content of a backup.
+type Archive struct {
+	FormatVersion int       `json:"formatVersion"`
+	Project       string    `json:"project"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Secrets       []Secret  `json:"secrets"`
+}
+
+// Secret is a secret with the metadata it is recreated with and its
+// enabled versions, oldest first.
+type Secret struct {
+	Name              string            `json:"name"`
+	Location          string            `json:"location,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	Replication       string            `json:"replication,omitempty"`
+	Locations         []string          `json:"locations,omitempty"`
+	NextRotationTime  time.Time         `json:"nextRotationTime,omitzero"`
+	RotationPeriod    time.Duration     `json:"rotationPeriod,omitempty"`
+	Topics            []string          `json:"topics,omitempty"`
+	ExpireTime        time.Time         `json:"expireTime,omitzero"`
+	VersionDestroyTTL time.Duration     `json:"versionDestroyTTL,omitempty"`
+	Versions          []Version         `json:"versions"`
+}
+
+type Version struct {
+	Number  int      `json:"number"`
+	Aliases []string `json:"aliases,omitempty"`
+	Data    []byte   `json:"data"`
+}
+
+// Snapshot archives every secret of the project with its latest enabled
+// version or, with allVersions, every enabled one. It fails on the first
+// secret that cannot be read, so that a backup is never silently partial.
+// The filter of c is ignored for the same reason.
+func Snapshot(c client.Client, project string, allVersions bool, now time.Time) (Archive, error) {
+	archive := Archive{FormatVersion: formatVersion, Project: project, CreatedAt: now.UTC()}
+
+	secretInfos, err := c.AllSecrets()
+	if err != nil {
+		return Archive{}, err
+	}
+	for _, secretInfo := range secretInfos {
+		secret := fromSecretInfo(secretInfo)
+
+		versions, err := c.GetSecretVersions(secretInfo.FullPath)
+		if err != nil {
+			return Archive{}, fmt.Errorf("%s: %w", secretInfo.Name, err)
+		}
+		slices.SortFunc(versions, func(a, b client.Version) int { return a.Version - b.Version })
+		latest := client.LatestEnabled(versions)
+
+		for _, version := range versions {
+			if !strings.EqualFold(version.State, "enabled") || (!allVersions && version.Version != latest) {
+				continue
+			}
+			data, err := c.GetSecretVersion(secretInfo.FullPath, strconv.Itoa(version.Version))
+			if err != nil {
+				return Archive{}, fmt.Errorf("%s: %w", secretInfo.Name, err)
+			}
+			secret.Versions = append(secret.Versions, Version{Number: version.Version, Aliases: version.Aliases, Data: data})
+		}
+		archive.Secrets = append(archive.Secrets, secret)
+	}
+	return archive, nil
+}
+
+func fromSecretInfo(secretInfo client.SecretInfo) Secret {
+	return Secret{
+		Name:              secretInfo.Name,
+		Location:          secretInfo.Location,
+		Labels:            secretInfo.Labels,
+		Annotations:       secretInfo.Annotations,
+		Replication:       secretInfo.Replication,
+		Locations:         secretInfo.Locations,
+		NextRotationTime:  secretInfo.NextRotationTime,
+		RotationPeriod:    secretInfo.RotationPeriod,
+		Topics:            secretInfo.Topics,
+		ExpireTime:        secretInfo.ExpireTime,
+		VersionDestroyTTL: secretInfo.VersionDestroyTTL,
+	}
+}
+
+// VersionCount counts the versions kept in the archive.
+func (a Archive) VersionCount() int {
+	count := 0
+	for _, secret := range a.Secrets {
+		count += len(secret.Versions)
+	}
+	return count
+}
+
+// Write encrypts the archive to recipients, age X25519 public keys, as
+// gzipped JSON.
+func Write(w io.Writer, archive Archive, recipients []string) error {
+	if len(recipients) == 0 {
+		return errors.New("a backup needs at least one age recipient")
+	}
+	var parsed []age.Recipient
+	for _, recipient := range recipients {
+		r, err := age.ParseX25519Recipient(recipient)
+		if err != nil {
+			return fmt.Errorf("invalid age recipient %q: %w", recipient, err)
+		}
+		parsed = append(parsed, r)
+	}
+
+	encrypted, err := age.Encrypt(w, parsed...)
+	if err != nil {
+		return err
+	}
+	compressed := gzip.NewWriter(encrypted)
+	if err := json.NewEncoder(compressed).Encode(archive); err != nil {
+		return err
+	}
+	if err := compressed.Close(); err != nil {
+		return err
+	}
+	return encrypted.Close()
+}
+
+// Read decrypts an archive written by Write with one of identities.
+func Read(r io.Reader, identities []age.Identity) (Archive, error) {
+	decrypted, err := age.Decrypt(r, identities...)
+	if err != nil {
+		return Archive{}, fmt.Errorf("failed to decrypt the backup: %w", err)
+	}
+	decompressed, err := gzip.NewReader(decrypted)
+	if err != nil {
+		return Archive{}, fmt.Errorf("not a backup of smm: %w", err)
+	}
+	defer decompressed.Close()
+
+	var archive Archive
+	if err := json.NewDecoder(decompressed).Decode(&archive); err != nil {
+		return Archive{}, fmt.Errorf("not a backup of smm: %w", err)
+	}
+	if archive.FormatVersion != formatVersion {
+		return Archive{}, fmt.Errorf("unsupported backup format %d", archive.FormatVersion)
+	}
+	return archive, nil
+}
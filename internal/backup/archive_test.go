@@ -0,0 +1,83 @@
+package backup
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"smm/internal/client"
+)
+
+func TestSnapshot(t *testing.T) {
+	fake, _ := client.NewFakeClient("test-project")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	latest, err := Snapshot(fake, "test-project", false, now)
+	require.NoError(t, err)
+	all, err := Snapshot(fake, "test-project", true, now)
+	require.NoError(t, err)
+
+	secretInfos, _ := fake.Secrets()
+	assert.Len(t, latest.Secrets, len(secretInfos))
+	assert.Equal(t, len(secretInfos), latest.VersionCount())
+	assert.Greater(t, all.VersionCount(), latest.VersionCount())
+	assert.Equal(t, now, latest.CreatedAt)
+
+	secret := all.Secrets[0]
+	assert.Equal(t, secretInfos[0].Labels, secret.Labels)
+	assert.Equal(t, secretInfos[0].Annotations, secret.Annotations)
+	for i := 1; i < len(secret.Versions); i++ {
+		assert.Less(t, secret.Versions[i-1].Number, secret.Versions[i].Number)
+	}
+	newest := secret.Versions[len(secret.Versions)-1]
+	assert.Equal(t, newest, latest.Secrets[0].Versions[0])
+	data, _ := fake.GetSecretVersion(secretInfos[0].FullPath, strconv.Itoa(newest.Number))
+	assert.Equal(t, data, newest.Data)
+}
+
+func TestSnapshotIgnoresFilter(t *testing.T) {
+	fake, _ := client.NewFakeClient("test-project")
+	all, _ := fake.Secrets()
+	require.NoError(t, fake.SetFilter("name:"+all[0].Name))
+
+	archive, err := Snapshot(fake, "test-project", false, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, archive.Secrets, len(all))
+	assert.Equal(t, "name:"+all[0].Name, fake.Filter())
+}
+
+func TestWriteRead(t *testing.T) {
+	identity, _ := age.GenerateX25519Identity()
+	other, _ := age.GenerateX25519Identity()
+	archive := Archive{
+		FormatVersion: formatVersion,
+		Project:       "test-project",
+		CreatedAt:     time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Secrets: []Secret{{
+			Name:     "api-key",
+			Labels:   map[string]string{"team": "payments"},
+			Versions: []Version{{Number: 3, Aliases: []string{"current"}, Data: []byte("abc123")}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, archive, []string{identity.Recipient().String()}))
+	assert.NotContains(t, buf.String(), "abc123")
+
+	read, err := Read(bytes.NewReader(buf.Bytes()), []age.Identity{identity})
+	require.NoError(t, err)
+	assert.Equal(t, archive, read)
+
+	_, err = Read(bytes.NewReader(buf.Bytes()), []age.Identity{other})
+	assert.ErrorContains(t, err, "failed to decrypt")
+}
+
+func TestWriteRecipients(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, Write(&buf, Archive{}, nil))
+	assert.ErrorContains(t, Write(&buf, Archive{}, []string{"not-a-key"}), "invalid age recipient")
+}
@@ -0,0 +1,212 @@
+package backup
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"smm/internal/bootstrap"
+	"smm/internal/client"
+	"smm/internal/config"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+)
+
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// newClient loads the config and connects to projectId or, when it is
+// empty, to the default project.
+func newClient(projectId string) (client.Client, string, error) {
+	_ = config.Load()
+	projectId = config.ResolveProjectId(projectId)
+	if projectId == "" {
+		return nil, "", fmt.Errorf("no project given, use -p or configure a default project")
+	}
+	c, err := client.New(projectId, config.GetTypeByProjectId(projectId), bootstrap.ClientOptions(projectId))
+	return c, projectId, err
+}
+
+// Main runs `smm backup` with the arguments following it, returning the
+// exit code. projectId, given to smm before the command, is the default of
+// its -p.
+func Main(args []string, projectId string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&projectId, "p", projectId, "Project ID to back up")
+	output := flags.String("o", "", "Archive to write, <project>-<time>.smm.age by default")
+	allVersions := flags.Bool("all-versions", false, "Keep every enabled version instead of the latest one")
+	var recipients listFlag
+	flags.Var(&recipients, "r", "age recipient to encrypt to, repeatable")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: smm backup -r age1... [flags]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if len(recipients) == 0 {
+		fmt.Fprintln(stderr, "a backup needs at least one age recipient, use -r")
+		return 2
+	}
+
+	c, project, err := newClient(projectId)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer c.Close()
+
+	now := time.Now()
+	archive, err := Snapshot(c, project, *allVersions, now)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("%s-%s.smm.age", project, now.Format("20060102-150405"))
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err := Write(f, archive, recipients); err != nil {
+		f.Close()
+		os.Remove(path)
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Backed up %d secrets and %d versions of %s to %s\n", len(archive.Secrets), archive.VersionCount(), project, path)
+	return 0
+}
+
+// RestoreMain runs `smm restore` with the arguments following it, returning
+// the exit code. projectId, given to smm before the command, is the default
+// of its -p.
+func RestoreMain(args []string, projectId string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&projectId, "p", projectId, "Project ID to restore into")
+	conflictName := flags.String("conflict", string(ConflictSkip), "For existing secrets: skip, update or fail")
+	dryRun := flags.Bool("dry-run", false, "Print the plan without changing anything")
+	confirm := flags.String("confirm", "", "Project ID, required by projects with confirm-typed protection")
+	var identityFiles listFlag
+	flags.Var(&identityFiles, "i", "age identity file to decrypt with, repeatable")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: smm restore -i key.txt [flags] archive")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || len(identityFiles) == 0 {
+		flags.Usage()
+		return 2
+	}
+	conflict, err := ParseConflict(*conflictName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	archive, err := readArchive(flags.Arg(0), identityFiles)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	c, project, err := newClient(projectId)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer c.Close()
+	steps, err := Plan(c, archive, project, conflict)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Restoring the backup of %s taken %s into %s: %s\n", archive.Project, archive.CreatedAt.Format(time.RFC3339), project, Summary(steps))
+	for _, step := range steps {
+		fmt.Fprintln(stdout, "  "+formatStep(step))
+	}
+	if *dryRun {
+		return 0
+	}
+	if config.GetProtectionByProjectId(project) == client.ProtectionConfirmTyped && *confirm != project {
+		fmt.Fprintf(stderr, "%s is protected, run again with -confirm %s\n", project, project)
+		return 1
+	}
+
+	failed := 0
+	for _, step := range steps {
+		if err := Apply(c, step, time.Now()); err != nil {
+			failed++
+			fmt.Fprintf(stderr, "✖ %s: %v\n", step.Name, err)
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(stderr, "%d of %d secrets failed to restore\n", failed, len(steps))
+		return 1
+	}
+	fmt.Fprintln(stdout, "Restore finished")
+	return 0
+}
+
+func readArchive(path string, identityFiles []string) (Archive, error) {
+	var identities []age.Identity
+	for _, identityFile := range identityFiles {
+		f, err := os.Open(identityFile)
+		if err != nil {
+			return Archive{}, err
+		}
+		parsed, err := age.ParseIdentities(f)
+		f.Close()
+		if err != nil {
+			return Archive{}, fmt.Errorf("%s: %w", identityFile, err)
+		}
+		identities = append(identities, parsed...)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return Archive{}, err
+	}
+	defer f.Close()
+	return Read(f, identities)
+}
+
+// formatStep describes a step of the plan: + created, ~ new version and =
+// skipped.
+func formatStep(step Step) string {
+	name := step.Name
+	if step.Location != "" {
+		name += " [" + step.Location + "]"
+	}
+	switch step.Action {
+	case Create:
+		return fmt.Sprintf("+ %s (%d versions)", name, len(step.Versions))
+	case Update:
+		return "~ " + name + " (new version)"
+	}
+	return "= " + name + " (exists)"
+}
@@ -0,0 +1,172 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"smm/internal/client"
+	"time"
+)
+
+// Conflict is what restoring does to a secret that already exists in the
+// target project.
+type Conflict string
+
+const (
+	// ConflictSkip leaves existing secrets untouched.
+	ConflictSkip Conflict = "skip"
+	// ConflictUpdate adds the latest backed up version to existing secrets
+	// whose latest version differs.
+	ConflictUpdate Conflict = "update"
+	// ConflictFail refuses to restore anything if a secret exists.
+	ConflictFail Conflict = "fail"
+)
+
+func ParseConflict(name string) (Conflict, error) {
+	switch conflict := Conflict(name); conflict {
+	case ConflictSkip, ConflictUpdate, ConflictFail:
+		return conflict, nil
+	}
+	return "", fmt.Errorf("unknown conflict handling %q, use %s, %s or %s", name, ConflictSkip, ConflictUpdate, ConflictFail)
+}
+
+type Action int
+
+const (
+	// Create recreates the secret with every backed up version.
+	Create Action = iota
+	// Update adds the latest backed up version to the existing secret.
+	Update
+	// Skip leaves the existing secret as it is.
+	Skip
+)
+
+// Step is what restoring a secret of the archive does to the target
+// project.
+type Step struct {
+	Secret
+	Action Action
+	// FullPath is the path of the existing secret, for updates and skips.
+	FullPath string
+}
+
+// Plan decides how each secret of the archive is restored into project, the
+// project of c, following conflict for the secrets that exist there already.
+// Pub/Sub topics belong to the backed up project, so they are dropped when
+// restoring into another one. Secrets outside the filter of c count as
+// existing too.
+func Plan(c client.Client, archive Archive, project string, conflict Conflict) ([]Step, error) {
+	secretInfos, err := c.AllSecrets()
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]string{}
+	for _, secretInfo := range secretInfos {
+		existing[secretInfo.Location+"/"+secretInfo.Name] = secretInfo.FullPath
+	}
+
+	steps := make([]Step, len(archive.Secrets))
+	for i, secret := range archive.Secrets {
+		fullPath, found := existing[secret.Location+"/"+secret.Name]
+		if project != archive.Project {
+			secret.Topics = nil
+		}
+		step := Step{Secret: secret, FullPath: fullPath}
+		switch {
+		case !found:
+			step.Action = Create
+		case conflict == ConflictFail:
+			return nil, fmt.Errorf("%s already exists in the project", secret.Name)
+		case conflict == ConflictUpdate && len(secret.Versions) > 0:
+			step.Action = Update
+			latest := secret.Versions[len(secret.Versions)-1].Data
+			if current, err := c.GetSecret(fullPath); err == nil && bytes.Equal(current, latest) {
+				step.Action = Skip
+			}
+		default:
+			step.Action = Skip
+		}
+		steps[i] = step
+	}
+	return steps, nil
+}
+
+// Apply carries out step. A recreated secret gets its versions renumbered
+// from 1, with the aliases moved to the versions they pointed to. If any of
+// its versions or aliases cannot be restored the secret is deleted again, so
+// that running the restore once more creates it instead of skipping it.
+func Apply(c client.Client, step Step, now time.Time) error {
+	switch step.Action {
+	case Update:
+		return c.AddSecretVersion(step.FullPath, step.Versions[len(step.Versions)-1].Data)
+	case Skip:
+		return nil
+	}
+
+	created, err := c.CreateSecretFrom(step.template(now))
+	if err != nil {
+		return err
+	}
+	if err := restoreVersions(c, created.FullPath, step.Versions); err != nil {
+		if deleteErr := c.DeleteSecret(created.FullPath); deleteErr != nil {
+			return fmt.Errorf("%w, and the partially restored secret could not be deleted: %v", err, deleteErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func restoreVersions(c client.Client, fullPath string, versions []Version) error {
+	for i, version := range versions {
+		if err := c.AddSecretVersion(fullPath, version.Data); err != nil {
+			return err
+		}
+		for _, alias := range version.Aliases {
+			if err := c.SetVersionAlias(fullPath, alias, i+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// template is the secret to create. Settings that can no longer be applied
+// are moved forward or dropped: a rotation time that has passed moves on by
+// whole periods and an expiration that has passed is dropped.
+func (s Secret) template(now time.Time) client.SecretInfo {
+	template := client.SecretInfo{
+		Name:              s.Name,
+		Location:          s.Location,
+		Labels:            s.Labels,
+		Annotations:       s.Annotations,
+		Replication:       s.Replication,
+		Locations:         s.Locations,
+		NextRotationTime:  s.NextRotationTime,
+		RotationPeriod:    s.RotationPeriod,
+		Topics:            s.Topics,
+		ExpireTime:        s.ExpireTime,
+		VersionDestroyTTL: s.VersionDestroyTTL,
+	}
+
+	if !template.NextRotationTime.IsZero() && !template.NextRotationTime.After(now) {
+		if template.RotationPeriod > 0 {
+			periods := now.Sub(template.NextRotationTime)/template.RotationPeriod + 1
+			template.NextRotationTime = template.NextRotationTime.Add(periods * template.RotationPeriod)
+		} else {
+			template.NextRotationTime = time.Time{}
+		}
+	}
+	if !template.ExpireTime.IsZero() && !template.ExpireTime.After(now) {
+		template.ExpireTime = time.Time{}
+	}
+	return template
+}
+
+// Summary counts the steps by action, e.g. "3 created, 1 updated, 2
+// skipped".
+func Summary(steps []Step) string {
+	counts := map[Action]int{}
+	for _, step := range steps {
+		counts[step.Action]++
+	}
+	return fmt.Sprintf("%d created, %d updated, %d skipped", counts[Create], counts[Update], counts[Skip])
+}
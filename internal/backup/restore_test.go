@@ -0,0 +1,148 @@
+package backup
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"smm/internal/client"
+)
+
+func restoreArchive() Archive {
+	return Archive{
+		FormatVersion: formatVersion,
+		Project:       "source-project",
+		Secrets: []Secret{
+			{
+				Name:        "api-key",
+				Labels:      map[string]string{"team": "payments"},
+				Annotations: map[string]string{"owner": "ops"},
+				Topics:      []string{"projects/source-project/topics/rotations"},
+				Versions: []Version{
+					{Number: 2, Data: []byte("old")},
+					{Number: 5, Aliases: []string{"current"}, Data: []byte("new")},
+				},
+			},
+			{Name: "db-url", Versions: []Version{{Number: 1, Data: []byte("postgres://backup")}}},
+		},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	fake, _ := client.NewFakeClient("target-project")
+	existing, _ := fake.CreateSecret("db-url", client.SecretSettings{}, []byte("postgres://live"))
+
+	steps, err := Plan(fake, restoreArchive(), "target-project", ConflictSkip)
+	require.NoError(t, err)
+	assert.Equal(t, Create, steps[0].Action)
+	assert.Equal(t, Skip, steps[1].Action)
+	assert.Equal(t, existing.FullPath, steps[1].FullPath)
+	assert.Equal(t, "1 created, 0 updated, 1 skipped", Summary(steps))
+
+	steps, err = Plan(fake, restoreArchive(), "target-project", ConflictUpdate)
+	require.NoError(t, err)
+	assert.Equal(t, Update, steps[1].Action)
+
+	_ = fake.AddSecretVersion(existing.FullPath, []byte("postgres://backup"))
+	steps, err = Plan(fake, restoreArchive(), "target-project", ConflictUpdate)
+	require.NoError(t, err)
+	assert.Equal(t, Skip, steps[1].Action)
+
+	_, err = Plan(fake, restoreArchive(), "target-project", ConflictFail)
+	assert.ErrorContains(t, err, "db-url already exists")
+}
+
+func TestPlanIgnoresFilter(t *testing.T) {
+	fake, _ := client.NewFakeClient("target-project")
+	existing, _ := fake.CreateSecret("db-url", client.SecretSettings{}, []byte("postgres://live"))
+	require.NoError(t, fake.SetFilter("name:api-key"))
+
+	steps, err := Plan(fake, restoreArchive(), "target-project", ConflictSkip)
+	require.NoError(t, err)
+	assert.Equal(t, Skip, steps[1].Action)
+	assert.Equal(t, existing.FullPath, steps[1].FullPath)
+}
+
+func TestPlanTopics(t *testing.T) {
+	fake, _ := client.NewFakeClient("target-project")
+
+	steps, _ := Plan(fake, restoreArchive(), "target-project", ConflictSkip)
+	assert.Nil(t, steps[0].Topics)
+
+	steps, _ = Plan(fake, restoreArchive(), "source-project", ConflictSkip)
+	assert.Equal(t, []string{"projects/source-project/topics/rotations"}, steps[0].Topics)
+}
+
+// aliasRecorder records the aliases set through it.
+type aliasRecorder struct {
+	client.Client
+	aliases map[string]int
+}
+
+func (a aliasRecorder) SetVersionAlias(secretName, alias string, version int) error {
+	a.aliases[alias] = version
+	return a.Client.SetVersionAlias(secretName, alias, version)
+}
+
+func TestApply(t *testing.T) {
+	fake, _ := client.NewFakeClient("target-project")
+	existing, _ := fake.CreateSecret("db-url", client.SecretSettings{}, []byte("postgres://live"))
+	steps, _ := Plan(fake, restoreArchive(), "target-project", ConflictUpdate)
+
+	recorder := aliasRecorder{Client: fake, aliases: map[string]int{}}
+	for _, step := range steps {
+		require.NoError(t, Apply(recorder, step, time.Now()))
+	}
+	assert.Equal(t, map[string]int{"current": 2}, recorder.aliases)
+
+	created, err := fake.GetSecretInfo("projects/target-project/secrets/api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "payments", created.Labels["team"])
+	assert.Equal(t, "ops", created.Annotations["owner"])
+	data, _ := fake.GetSecret(created.FullPath)
+	assert.Equal(t, "new", string(data))
+
+	data, _ = fake.GetSecret(existing.FullPath)
+	assert.Equal(t, "postgres://backup", string(data))
+}
+
+// failingVersions fails to add the versions of a secret.
+type failingVersions struct {
+	client.Client
+}
+
+func (f failingVersions) AddSecretVersion(secretName string, payload []byte) error {
+	return errors.New("quota exceeded")
+}
+
+func TestApplyDeletesPartialSecret(t *testing.T) {
+	fake, _ := client.NewFakeClient("target-project")
+	steps, _ := Plan(fake, restoreArchive(), "target-project", ConflictSkip)
+
+	assert.ErrorContains(t, Apply(failingVersions{Client: fake}, steps[0], time.Now()), "quota exceeded")
+
+	steps, _ = Plan(fake, restoreArchive(), "target-project", ConflictSkip)
+	assert.Equal(t, Create, steps[0].Action)
+}
+
+func TestTemplate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	secret := Secret{
+		Name:             "api-key",
+		NextRotationTime: now.Add(-36 * time.Hour),
+		RotationPeriod:   24 * time.Hour,
+		ExpireTime:       now.Add(-time.Hour),
+	}
+
+	template := secret.template(now)
+	assert.Equal(t, now.Add(12*time.Hour), template.NextRotationTime)
+	assert.True(t, template.ExpireTime.IsZero())
+
+	secret.RotationPeriod = 0
+	secret.ExpireTime = now.Add(time.Hour)
+	template = secret.template(now)
+	assert.True(t, template.NextRotationTime.IsZero())
+	assert.Equal(t, now.Add(time.Hour), template.ExpireTime)
+}
@@ -2,6 +2,7 @@ package bootstrap
 
 import (
 	"os"
+	"smm/internal/client"
 	"smm/internal/config"
 
 	"github.com/rs/zerolog"
@@ -24,5 +25,27 @@ func SetLog() {
 		log.Fatal().Err(err).Msg("Error opening log file")
 	}
 
-	log.Logger = log.Output(file)
+	log.Logger = zerolog.New(file).With().Timestamp().Logger()
+}
+
+// DisableLog silences logging until SetLog, so that the commands printing a
+// report do not get log lines mixed into it.
+func DisableLog() {
+	log.Logger = log.Logger.Level(zerolog.Disabled)
+}
+
+// ClientOptions gathers the client settings configured for the project.
+func ClientOptions(projectId string) client.Options {
+	project := config.GetProject(projectId)
+	return client.Options{
+		Filter:     config.GetFilterByProjectId(projectId),
+		Locations:  config.GetLocationsByProjectId(projectId),
+		Protection: config.GetProtectionByProjectId(projectId),
+		Credentials: client.Credentials{
+			File:                      config.ExpandHome(project.Credentials),
+			GcloudConfig:              project.GcloudConfig,
+			ImpersonateServiceAccount: project.Impersonate,
+			Delegates:                 project.Delegates,
+		},
+	}
 }
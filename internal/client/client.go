@@ -1,11 +1,104 @@
 package client
 
+import (
+	"context"
+
+	"smm/internal/search"
+)
+
 type Client interface {
 	GetSecretVersions(secretName string) ([]Version, error)
 	GetSecret(secretName string) ([]byte, error)
 	GetSecretVersion(secretName, version string) ([]byte, error)
+	// AddSecretVersion adds a version to the secret at a full path or, for
+	// global secrets, with a bare name.
 	AddSecretVersion(secretName string, payload []byte) error
-	SearchInSecrets(query string) ([]SecretInfo, error)
+	SearchInSecrets(ctx context.Context, query *search.Query, opts SearchOptions, events chan<- SearchEvent) error
 	Secrets() ([]SecretInfo, error)
+	// AllSecrets lists every secret of the project, ignoring the filter, for
+	// the commands that must not miss any of them.
+	AllSecrets() ([]SecretInfo, error)
 	GetSecretInfo(fullPath string) (SecretInfo, error)
+	// SetVersionAlias points alias to version, moving it if another version
+	// of the secret had it.
+	SetVersionAlias(secretName, alias string, version int) error
+	// CreateSecret creates secretName with the given settings and payload as
+	// its first version.
+	CreateSecret(secretName string, settings SecretSettings, payload []byte) (SecretInfo, error)
+	// CreateSecretFrom creates a secret without versions named and placed
+	// like template, with its settings, labels, annotations, replication
+	// and version destroy TTL. Its encryption keys are not copied.
+	CreateSecretFrom(template SecretInfo) (SecretInfo, error)
+	// DeleteSecret deletes the secret and every version of it.
+	DeleteSecret(fullPath string) error
+	UpdateSecretSettings(fullPath string, settings SecretSettings) error
+	// SetLabels replaces the labels of the secret.
+	SetLabels(fullPath string, labels map[string]string) error
+	GetIamPolicy(fullPath string) (Policy, error)
+	// SetIamPolicy replaces the bindings of the secret, failing if the policy
+	// changed since its etag was read.
+	SetIamPolicy(fullPath string, policy Policy) error
+	// TestIamPermissions returns the subset of permissions the caller holds
+	// on resource, either a secret path or "projects/<id>".
+	TestIamPermissions(resource string, permissions []string) ([]string, error)
+	// Identity describes the account the project is accessed as.
+	Identity() string
+	// Close releases the connections of the client, which is not used
+	// afterwards.
+	Close() error
+	// SetFilter restricts Secrets and searches to the secrets matching a
+	// ListSecrets filter expression; an empty filter lists everything.
+	SetFilter(filter string) error
+	// Filter returns the filter set with SetFilter.
+	Filter() string
+}
+
+// Options are the per-project settings of a client.
+type Options struct {
+	// Filter is a ListSecrets filter expression limiting the listed secrets.
+	Filter string
+	// Locations are the regions whose regional secrets are listed next to
+	// the global ones.
+	Locations []string
+	// Protection is the protection level of the project; a read-only
+	// project gets a client rejecting every change.
+	Protection string
+	// Credentials choose the identity the project is accessed with.
+	Credentials Credentials
+}
+
+// New returns the client for a project of the given type. Any type other
+// than "gcp" gets the fake client.
+func New(projectID, projectType string, opts Options) (Client, error) {
+	if err := ValidateProtection(opts.Protection); err != nil {
+		return nil, err
+	}
+	if err := opts.Credentials.Validate(); err != nil {
+		return nil, err
+	}
+
+	c, err := newClient(projectID, projectType, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Protection == ProtectionReadOnly {
+		return NewReadOnly(c), nil
+	}
+	return c, nil
+}
+
+func newClient(projectID, projectType string, opts Options) (Client, error) {
+	if projectType == "gcp" {
+		return NewGcp(projectID, opts)
+	}
+
+	fake, err := NewFakeClient(projectID)
+	if err != nil {
+		return nil, err
+	}
+	fake.locations = opts.Locations
+	if err := fake.SetFilter(opts.Filter); err != nil {
+		return nil, err
+	}
+	return fake, nil
 }
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// Credentials choose the identity a project is accessed with. The zero
+// Credentials use Application Default Credentials.
+type Credentials struct {
+	// File is a service account key or authorized user JSON file.
+	File string
+	// GcloudConfig is the gcloud configuration whose account is used.
+	GcloudConfig string
+	// ImpersonateServiceAccount acts on behalf of the other credentials.
+	ImpersonateServiceAccount string
+	// Delegates is the delegation chain to ImpersonateServiceAccount.
+	Delegates []string
+}
+
+// Validate checks that the credentials choose a single base identity.
+func (c Credentials) Validate() error {
+	if c.File != "" && c.GcloudConfig != "" {
+		return fmt.Errorf("credentials file and gcloud configuration are mutually exclusive")
+	}
+	if len(c.Delegates) > 0 && c.ImpersonateServiceAccount == "" {
+		return fmt.Errorf("delegates need a service account to impersonate")
+	}
+	return nil
+}
+
+// clientOptions returns the options authenticating the API clients.
+func (c Credentials) clientOptions(ctx context.Context) ([]option.ClientOption, error) {
+	var opts []option.ClientOption
+	switch {
+	case c.File != "":
+		opts = append(opts, option.WithCredentialsFile(c.File))
+	case c.GcloudConfig != "":
+		opts = append(opts, option.WithTokenSource(oauth2.ReuseTokenSource(nil, gcloudTokenSource{configuration: c.GcloudConfig})))
+	}
+
+	if c.ImpersonateServiceAccount == "" {
+		return opts, nil
+	}
+	tokenSource, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: c.ImpersonateServiceAccount,
+		Scopes:          []string{cloudPlatformScope},
+		Delegates:       c.Delegates,
+	}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate %s: %w", c.ImpersonateServiceAccount, err)
+	}
+	return []option.ClientOption{option.WithTokenSource(tokenSource)}, nil
+}
+
+// identity describes who the credentials act as, as precisely as can be
+// told without calling an API.
+func (c Credentials) identity(ctx context.Context) string {
+	if c.ImpersonateServiceAccount != "" {
+		if len(c.Delegates) > 0 {
+			return c.ImpersonateServiceAccount + " via " + strings.Join(c.Delegates, " → ")
+		}
+		return c.ImpersonateServiceAccount
+	}
+
+	switch {
+	case c.GcloudConfig != "":
+		helper, err := gcloudConfigHelper(c.GcloudConfig)
+		if err != nil || helper.Configuration.Properties.Core.Account == "" {
+			return "gcloud configuration " + c.GcloudConfig
+		}
+		return helper.Configuration.Properties.Core.Account
+	case c.File != "":
+		data, err := os.ReadFile(c.File)
+		if email := clientEmail(data); err == nil && email != "" {
+			return email
+		}
+		return "credentials file " + filepath.Base(c.File)
+	}
+
+	credentials, err := google.FindDefaultCredentials(ctx, cloudPlatformScope)
+	if err == nil {
+		if email := clientEmail(credentials.JSON); email != "" {
+			return email
+		}
+	}
+	return "application default credentials"
+}
+
+// clientEmail returns the service account of a credentials JSON file, empty
+// for user credentials.
+func clientEmail(data []byte) string {
+	var file struct {
+		ClientEmail string `json:"client_email"`
+	}
+	_ = json.Unmarshal(data, &file)
+	return file.ClientEmail
+}
+
+// gcloudHelperOutput is the part of `gcloud config config-helper` used here.
+type gcloudHelperOutput struct {
+	Configuration struct {
+		Properties struct {
+			Core struct {
+				Account string `json:"account"`
+			} `json:"core"`
+		} `json:"properties"`
+	} `json:"configuration"`
+	Credential struct {
+		AccessToken string    `json:"access_token"`
+		TokenExpiry time.Time `json:"token_expiry"`
+	} `json:"credential"`
+}
+
+// gcloudConfigHelper runs gcloud for the credentials of a configuration. It
+// is a variable so that tests do not need gcloud.
+var gcloudConfigHelper = func(configuration string) (gcloudHelperOutput, error) {
+	var helper gcloudHelperOutput
+	out, err := exec.Command("gcloud", "config", "config-helper", "--configuration", configuration, "--format", "json").Output()
+	if err != nil {
+		return helper, fmt.Errorf("failed to get the credentials of gcloud configuration %s: %w", configuration, err)
+	}
+	if err := json.Unmarshal(out, &helper); err != nil {
+		return helper, fmt.Errorf("failed to parse gcloud config-helper output: %w", err)
+	}
+	return helper, nil
+}
+
+// gcloudTokenSource gets access tokens from a gcloud configuration, which
+// refreshes them as needed.
+type gcloudTokenSource struct {
+	configuration string
+}
+
+func (s gcloudTokenSource) Token() (*oauth2.Token, error) {
+	helper, err := gcloudConfigHelper(s.configuration)
+	if err != nil {
+		return nil, err
+	}
+	if helper.Credential.AccessToken == "" {
+		return nil, fmt.Errorf("gcloud configuration %s has no credentials, run gcloud auth login", s.configuration)
+	}
+	return &oauth2.Token{AccessToken: helper.Credential.AccessToken, Expiry: helper.Credential.TokenExpiry}, nil
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialsValidate(t *testing.T) {
+	assert.NoError(t, Credentials{}.Validate())
+	assert.NoError(t, Credentials{GcloudConfig: "prod", ImpersonateServiceAccount: "sa@p.iam.gserviceaccount.com"}.Validate())
+	assert.Error(t, Credentials{File: "key.json", GcloudConfig: "prod"}.Validate())
+	assert.Error(t, Credentials{Delegates: []string{"a@p.iam.gserviceaccount.com"}}.Validate())
+
+	_, err := New("test-project", "fake", Options{Credentials: Credentials{File: "key.json", GcloudConfig: "prod"}})
+	assert.Error(t, err)
+}
+
+func TestCredentialsIdentity(t *testing.T) {
+	ctx := context.Background()
+
+	impersonated := Credentials{ImpersonateServiceAccount: "breakglass@p.iam.gserviceaccount.com", Delegates: []string{"ci@p.iam.gserviceaccount.com"}}
+	assert.Equal(t, "breakglass@p.iam.gserviceaccount.com via ci@p.iam.gserviceaccount.com", impersonated.identity(ctx))
+
+	file := filepath.Join(t.TempDir(), "key.json")
+	assert.NoError(t, os.WriteFile(file, []byte(`{"type":"service_account","client_email":"app@p.iam.gserviceaccount.com"}`), 0600))
+	assert.Equal(t, "app@p.iam.gserviceaccount.com", Credentials{File: file}.identity(ctx))
+
+	userFile := filepath.Join(t.TempDir(), "user.json")
+	assert.NoError(t, os.WriteFile(userFile, []byte(`{"type":"authorized_user"}`), 0600))
+	assert.Equal(t, "credentials file user.json", Credentials{File: userFile}.identity(ctx))
+}
+
+func TestGcloudConfigCredentials(t *testing.T) {
+	previous := gcloudConfigHelper
+	defer func() { gcloudConfigHelper = previous }()
+
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	gcloudConfigHelper = func(configuration string) (gcloudHelperOutput, error) {
+		var helper gcloudHelperOutput
+		helper.Configuration.Properties.Core.Account = configuration + "@example.com"
+		helper.Credential.AccessToken = "token"
+		helper.Credential.TokenExpiry = expiry
+		return helper, nil
+	}
+
+	assert.Equal(t, "prod@example.com", Credentials{GcloudConfig: "prod"}.identity(context.Background()))
+
+	token, err := gcloudTokenSource{configuration: "prod"}.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token.AccessToken)
+	assert.Equal(t, expiry, token.Expiry)
+}
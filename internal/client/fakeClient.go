@@ -2,21 +2,337 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"math/rand/v2"
+	"path/filepath"
+	"slices"
+	"smm/internal/search"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jaswdr/faker/v2"
 )
 
 type FakeClient struct {
+	projectID string
+	locations []string
+	state     *fakeState
+}
+
+// fakeState holds what the fake client keeps between calls. It is shared by
+// the copies of a FakeClient.
+type fakeState struct {
+	mu       sync.Mutex
+	filter   *Filter
+	aliases  map[string]map[string]int
+	settings map[string]SecretSettings
+	labels   map[string]map[string]string
+	created  []SecretInfo
+	payloads map[string][]byte
+	policies map[string]Policy
 }
 
 func NewFakeClient(projectId string) (FakeClient, error) {
-	return FakeClient{}, nil
+	state := &fakeState{
+		aliases:  map[string]map[string]int{},
+		settings: map[string]SecretSettings{},
+		labels:   map[string]map[string]string{},
+		payloads: map[string][]byte{},
+		policies: map[string]Policy{},
+	}
+	return FakeClient{projectID: projectId, state: state}, nil
+}
+
+// createdPayload returns the payload of a secret made with CreateSecret.
+func (f FakeClient) createdPayload(fullPath string) ([]byte, bool) {
+	if f.state == nil {
+		return nil, false
+	}
+
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	payload, ok := f.state.payloads[fullPath]
+	return payload, ok
+}
+
+func (f FakeClient) createdSecrets() []SecretInfo {
+	if f.state == nil {
+		return nil
+	}
+
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	return slices.Clone(f.state.created)
+}
+
+func (f FakeClient) CreateSecret(secretName string, settings SecretSettings, payload []byte) (SecretInfo, error) {
+	fullPath := fmt.Sprintf("projects/%s/secrets/%s", f.projectID, secretName)
+	secretInfo := SecretInfo{
+		Project:    f.projectID,
+		Name:       secretName,
+		FullPath:   fullPath,
+		CreateTime: time.Now(),
+	}
+
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	if _, ok := f.state.payloads[fullPath]; ok {
+		return SecretInfo{}, fmt.Errorf("secret %s already exists", secretName)
+	}
+	f.state.created = append(f.state.created, secretInfo)
+	f.state.payloads[fullPath] = payload
+	f.state.settings[fullPath] = settings
+
+	secretInfo.NextRotationTime = settings.NextRotationTime
+	secretInfo.RotationPeriod = settings.RotationPeriod
+	secretInfo.Topics = settings.Topics
+	secretInfo.ExpireTime = settings.ExpireTime
+	return secretInfo, nil
+}
+
+// CreateSecretFrom keeps the labels and annotations of template. The
+// replication of fake secrets is always derived from their path.
+func (f FakeClient) CreateSecretFrom(template SecretInfo) (SecretInfo, error) {
+	fullPath := fmt.Sprintf("projects/%s/secrets/%s", f.projectID, template.Name)
+	if template.Location != "" {
+		fullPath = fmt.Sprintf("projects/%s/locations/%s/secrets/%s", f.projectID, template.Location, template.Name)
+	}
+	secretInfo := SecretInfo{
+		Project:     f.projectID,
+		Location:    template.Location,
+		Name:        template.Name,
+		FullPath:    fullPath,
+		CreateTime:  time.Now(),
+		Labels:      maps.Clone(template.Labels),
+		Annotations: maps.Clone(template.Annotations),
+	}
+
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	if _, ok := f.state.payloads[fullPath]; ok {
+		return SecretInfo{}, fmt.Errorf("secret %s already exists", template.Name)
+	}
+	f.state.created = append(f.state.created, secretInfo)
+	f.state.payloads[fullPath] = nil
+	f.state.settings[fullPath] = template.Settings()
+
+	secretInfo.NextRotationTime = template.NextRotationTime
+	secretInfo.RotationPeriod = template.RotationPeriod
+	secretInfo.Topics = template.Topics
+	secretInfo.ExpireTime = template.ExpireTime
+	return secretInfo, nil
+}
+
+// DeleteSecret deletes a secret made with CreateSecret or CreateSecretFrom.
+// The generated secrets cannot be deleted.
+func (f FakeClient) DeleteSecret(fullPath string) error {
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	if _, ok := f.state.payloads[fullPath]; !ok {
+		return fmt.Errorf("secret %s cannot be deleted", fullPath)
+	}
+	f.state.created = slices.DeleteFunc(f.state.created, func(secretInfo SecretInfo) bool {
+		return secretInfo.FullPath == fullPath
+	})
+	delete(f.state.payloads, fullPath)
+	delete(f.state.settings, fullPath)
+	delete(f.state.labels, fullPath)
+	delete(f.state.aliases, fullPath)
+	return nil
+}
+
+// secretSettings returns the settings saved with UpdateSecretSettings or
+// otherwise derives them from the path: a third of the secrets rotate
+// monthly and a fifth expire within the next weeks.
+func (f FakeClient) secretSettings(fullPath string) SecretSettings {
+	if f.state != nil {
+		f.state.mu.Lock()
+		settings, ok := f.state.settings[fullPath]
+		f.state.mu.Unlock()
+		if ok {
+			return settings
+		}
+	}
+
+	seed := seedFromSecretName(fullPath + "_rotation")
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>32)))
+	today := time.Now().Truncate(24 * time.Hour)
+
+	var settings SecretSettings
+	if rng.IntN(3) == 0 {
+		settings.NextRotationTime = today.AddDate(0, 0, rng.IntN(60)-20)
+		settings.RotationPeriod = 30 * 24 * time.Hour
+		settings.Topics = []string{fmt.Sprintf("projects/%s/topics/secret-rotation", f.projectID)}
+	}
+	if rng.IntN(5) == 0 {
+		settings.ExpireTime = today.AddDate(0, 0, 1+rng.IntN(40))
+	}
+	return settings
+}
+
+func (f FakeClient) withSettings(secretInfo SecretInfo) SecretInfo {
+	settings := f.secretSettings(secretInfo.FullPath)
+	secretInfo.NextRotationTime = settings.NextRotationTime
+	secretInfo.RotationPeriod = settings.RotationPeriod
+	secretInfo.Topics = settings.Topics
+	secretInfo.ExpireTime = settings.ExpireTime
+	return secretInfo
+}
+
+// withMetadata adds a replication policy, encryption keys, an etag and a
+// destroy TTL derived from the path of the secret, and the labels set with
+// SetLabels.
+func (f FakeClient) withMetadata(secretInfo SecretInfo) SecretInfo {
+	seed := seedFromSecretName(secretInfo.FullPath + "_metadata")
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>32)))
+
+	secretInfo.Etag = fmt.Sprintf("\"%x\"", rng.Uint64()&0xffffffffffff)
+	secretInfo.Replication = "automatic"
+	if location := LocationOf(secretInfo.FullPath); location != "" {
+		secretInfo.Replication = "regional"
+		secretInfo.Locations = []string{location}
+	} else if rng.IntN(2) == 0 {
+		secretInfo.Replication = "user-managed"
+		secretInfo.Locations = []string{"europe-west1", "us-east1"}
+	}
+	if rng.IntN(3) == 0 {
+		secretInfo.KmsKeys = []string{fmt.Sprintf("projects/%s/locations/global/keyRings/secrets/cryptoKeys/smm", f.projectID)}
+	}
+	if rng.IntN(2) == 0 {
+		secretInfo.VersionDestroyTTL = 7 * 24 * time.Hour
+	}
+	if f.state != nil {
+		f.state.mu.Lock()
+		if labels, ok := f.state.labels[secretInfo.FullPath]; ok {
+			secretInfo.Labels = maps.Clone(labels)
+		}
+		f.state.mu.Unlock()
+	}
+	return secretInfo
+}
+
+// fakeReplicas returns the replication status of a version of secretInfo.
+func fakeReplicas(secretInfo SecretInfo, version int) []Replica {
+	keyVersion := ""
+	if len(secretInfo.KmsKeys) > 0 {
+		keyVersion = fmt.Sprintf("%s/cryptoKeyVersions/%d", secretInfo.KmsKeys[0], version)
+	}
+	if secretInfo.Replication == "automatic" {
+		return []Replica{{KmsKeyVersion: keyVersion}}
+	}
+
+	var replicas []Replica
+	for _, location := range secretInfo.Locations {
+		replicas = append(replicas, Replica{Location: location, KmsKeyVersion: keyVersion})
+	}
+	return replicas
+}
+
+// GetIamPolicy returns the policy saved with SetIamPolicy or a default one
+// granting access to the application and, for some secrets, a temporary
+// conditional grant.
+func (f FakeClient) GetIamPolicy(fullPath string) (Policy, error) {
+	if f.state != nil {
+		f.state.mu.Lock()
+		policy, ok := f.state.policies[fullPath]
+		f.state.mu.Unlock()
+		if ok {
+			return policy.clone(), nil
+		}
+	}
+
+	policy := Policy{
+		Version: 1,
+		Etag:    []byte("BwX1"),
+		Bindings: []Binding{
+			{Role: "roles/secretmanager.admin", Members: []string{"group:platform@example.com"}},
+			{Role: "roles/secretmanager.secretAccessor", Members: []string{fmt.Sprintf("serviceAccount:app@%s.iam.gserviceaccount.com", f.projectID)}},
+		},
+	}
+	if seedFromSecretName(fullPath+"_iam")%3 == 0 {
+		policy.Version = 3
+		policy.Bindings = append(policy.Bindings, Binding{
+			Role:    "roles/secretmanager.secretAccessor",
+			Members: []string{"user:contractor@example.com"},
+			Condition: Condition{
+				Title:      "Temporary access",
+				Expression: `request.time < timestamp("2030-01-01T00:00:00Z")`,
+			},
+		})
+	}
+	return policy, nil
+}
+
+func (f FakeClient) SetIamPolicy(fullPath string, policy Policy) error {
+	current, _ := f.GetIamPolicy(fullPath)
+	if string(current.Etag) != string(policy.Etag) {
+		return fmt.Errorf("failed to set IAM policy: the policy changed since it was read")
+	}
+
+	policy = policy.clone()
+	policy.Etag = []byte(fmt.Sprintf("%s+", current.Etag))
+
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	f.state.policies[fullPath] = policy
+	return nil
+}
+
+func (f FakeClient) Identity() string {
+	return "developer@example.com"
+}
+
+func (f FakeClient) Close() error {
+	return nil
+}
+
+// TestIamPermissions grants everything on the project, and only reading on
+// about one secret in six, so that the read-only case can be tried out.
+func (f FakeClient) TestIamPermissions(resource string, permissions []string) ([]string, error) {
+	readOnly := strings.Contains(resource, "/secrets/") && seedFromSecretName(resource+"_permissions")%6 == 0
+
+	var granted []string
+	for _, permission := range permissions {
+		if !readOnly || permission == PermissionAccessVersion {
+			granted = append(granted, permission)
+		}
+	}
+	return granted, nil
+}
+
+func (f FakeClient) UpdateSecretSettings(fullPath string, settings SecretSettings) error {
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	f.state.settings[fullPath] = settings
+	return nil
+}
+
+func (f FakeClient) SetLabels(fullPath string, labels map[string]string) error {
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	f.state.labels[fullPath] = maps.Clone(labels)
+	return nil
+}
+
+// secretAliases returns a copy of the alias map of a secret.
+func (f FakeClient) secretAliases(secretName string) map[string]int64 {
+	aliases := map[string]int64{}
+	if f.state == nil {
+		return aliases
+	}
+
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	for alias, version := range f.state.aliases[secretName] {
+		aliases[alias] = int64(version)
+	}
+	return aliases
 }
 
 func seedFromSecretName(secretName string) int64 {
@@ -44,9 +360,14 @@ func (f FakeClient) GetSecretVersions(secretName string) ([]Version, error) {
 	rng := rand.New(source)
 
 	numVersions := 1 + rng.IntN(5)
+	if _, ok := f.createdPayload(secretName); ok {
+		numVersions = 1
+	}
 	versions := make([]Version, numVersions)
 
 	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	aliases := versionAliases(f.secretAliases(secretName))
+	metadata := f.withMetadata(SecretInfo{FullPath: secretName})
 
 	for i := 0; i < numVersions; i++ {
 		versionNum := i + 1
@@ -58,12 +379,30 @@ func (f FakeClient) GetSecretVersions(secretName string) ([]Version, error) {
 			Version:   versionNum,
 			FullPath:  fmt.Sprintf("projects/test-project/secrets/%s/versions/%d", secretName, versionNum),
 			CreatedAt: baseTime.Add(timeOffset),
+			Aliases:   aliases[versionNum],
+			Replicas:  fakeReplicas(metadata, versionNum),
 		}
 	}
 
 	return versions, nil
 }
 
+// searchVersions lists the versions of secretName the way the API does, newest
+// first and with the path of their secret, so that version search results
+// can be read back like real ones.
+func (f FakeClient) searchVersions(secretName string) ([]Version, error) {
+	versions, err := f.GetSecretVersions(secretName)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(versions)
+	for i := range versions {
+		versions[i].Name = filepath.Base(secretName)
+		versions[i].FullPath = secretName
+	}
+	return versions, nil
+}
+
 func (f FakeClient) createEnvSecret(secretName string) []byte {
 	seed := seedFromSecretName(secretName)
 	source := rand.NewPCG(uint64(seed), uint64(seed>>32))
@@ -94,6 +433,9 @@ func (f FakeClient) createJsonSecret(secretName string) []byte {
 }
 
 func (f FakeClient) GetSecret(secretName string) ([]byte, error) {
+	if payload, ok := f.createdPayload(secretName); ok {
+		return payload, nil
+	}
 	if isSecretEnvType(secretName) {
 		return f.createEnvSecret(secretName), nil
 	}
@@ -127,64 +469,168 @@ func (f FakeClient) createJsonSecretVersion(secretName, version string) []byte {
 	return prettyJSON.Bytes()
 }
 
+// GetSecretVersion accepts a version number or one of the aliases set with
+// SetVersionAlias, like the API.
 func (f FakeClient) GetSecretVersion(secretName, version string) ([]byte, error) {
+	if version == "latest" {
+		return f.GetSecret(secretName)
+	}
+	if payload, ok := f.createdPayload(secretName); ok {
+		return payload, nil
+	}
+	if _, err := strconv.Atoi(version); err != nil {
+		number, ok := f.secretAliases(secretName)[version]
+		if !ok {
+			return nil, fmt.Errorf("alias %q not found in %s", version, secretName)
+		}
+		version = strconv.FormatInt(number, 10)
+	}
+
 	if isSecretEnvType(secretName) {
 		return f.createEnvSecretVersion(secretName, version), nil
 	}
 	return f.createJsonSecretVersion(secretName, version), nil
 }
 
+// AddSecretVersion replaces the payload of a secret made with CreateSecret
+// and ignores the others.
 func (f FakeClient) AddSecretVersion(secretName string, payload []byte) error {
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	if _, ok := f.state.payloads[secretName]; ok {
+		f.state.payloads[secretName] = payload
+	}
 	return nil
 }
 
-func (f FakeClient) SearchInSecrets(query string) ([]SecretInfo, error) {
-	seed := seedFromSecretName("search_" + query)
-	source := rand.NewPCG(uint64(seed), uint64(seed>>32))
-	rng := rand.New(source)
-	fk := faker.NewWithSeed(source)
+func (f FakeClient) SetVersionAlias(secretName, alias string, version int) error {
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
 
-	numResults := 2 + rng.IntN(5)
-	results := make([]SecretInfo, numResults)
+	if f.state.aliases[secretName] == nil {
+		f.state.aliases[secretName] = map[string]int{}
+	}
+	f.state.aliases[secretName][alias] = version
+	return nil
+}
 
-	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+func (f FakeClient) SearchInSecrets(ctx context.Context, query *search.Query, opts SearchOptions, events chan<- SearchEvent) error {
+	secretInfos, err := f.Secrets()
+	if err != nil {
+		return err
+	}
 
-	for i := 0; i < numResults; i++ {
-		secretName := fmt.Sprintf("%s-%s-secret", query, fk.Lorem().Word())
-		timeOffset := time.Duration(rng.Int64N(int64(time.Hour * 24 * 365)))
-		
-		results[i] = SecretInfo{
-			Name:        secretName,
-			FullPath:    fmt.Sprintf("projects/test-project/secrets/%s", secretName),
-			CreateTime:  baseTime.Add(timeOffset),
-			Labels:      map[string]string{"environment": "test", "type": "search-result"},
-			Annotations: map[string]string{"description": fmt.Sprintf("Search result for: %s", query)},
+	return scanSecrets(ctx, secretInfos, opts, events, func(ctx context.Context, secretInfo SecretInfo) (bool, []Version, error) {
+		if opts.AllVersions && query.NeedsPayload() {
+			versions, err := f.searchVersions(secretInfo.FullPath)
+			if err != nil {
+				return false, nil, err
+			}
+			return matchVersions(query, secretInfo, versions, func(version Version) ([]byte, error) {
+				return f.GetSecretVersion(secretInfo.FullPath, strconv.Itoa(version.Version))
+			})
 		}
+
+		found, err := matchSecret(query, secretInfo, func() ([]byte, error) {
+			return f.GetSecret(secretInfo.FullPath)
+		})
+		return found, nil, err
+	})
+}
+
+// SetFilter evaluates filter locally with the grammar of the ListSecrets API.
+func (f FakeClient) SetFilter(filter string) error {
+	parsed, err := ParseFilter(filter)
+	if err != nil {
+		return err
 	}
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	f.state.filter = parsed
+	return nil
+}
 
-	return results, nil
+func (f FakeClient) Filter() string {
+	return f.currentFilter().String()
+}
+
+func (f FakeClient) currentFilter() *Filter {
+	if f.state == nil {
+		return nil
+	}
+
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	return f.state.filter
 }
 
 func (f FakeClient) Secrets() ([]SecretInfo, error) {
+	return f.secrets(f.currentFilter())
+}
+
+func (f FakeClient) AllSecrets() ([]SecretInfo, error) {
+	return f.secrets(nil)
+}
+
+// secrets lists the generated and created secrets matching filter.
+func (f FakeClient) secrets(filter *Filter) ([]SecretInfo, error) {
 	seed := int64(12345)
 	source := rand.NewPCG(uint64(seed), uint64(seed>>32))
 	rng := rand.New(source)
 	fk := faker.NewWithSeed(source)
 
-	secrets := make([]SecretInfo, 30)
+	secrets := make([]SecretInfo, 0, 30)
 	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	for i := 0; i <= 29; i++ {
 		secretName := fmt.Sprintf("%s-secret", fk.Lorem().Word())
 		timeOffset := time.Duration(rng.Int64N(int64(time.Hour * 24 * 365)))
 		
-		secrets[i] = SecretInfo{
+		secretInfo := SecretInfo{
+			Project:     f.projectID,
 			Name:        secretName,
-			FullPath:    fmt.Sprintf("projects/test-project/secrets/%s", secretName),
+			FullPath:    fmt.Sprintf("projects/%s/secrets/%s", f.projectID, secretName),
 			CreateTime:  baseTime.Add(timeOffset),
 			Labels:      map[string]string{"environment": "test", "team": fk.Company().Name()},
 			Annotations: map[string]string{"description": fk.Lorem().Sentence(5)},
 		}
+		secretInfo = f.withMetadata(f.withSettings(secretInfo))
+		if filter.Match(secretInfo) {
+			secrets = append(secrets, secretInfo)
+		}
+	}
+
+	// A few regional secrets per location, drawn from their own seed so the
+	// global secrets stay the same.
+	for _, location := range f.locations {
+		seed := seedFromSecretName(location)
+		source := rand.NewPCG(uint64(seed), uint64(seed>>32))
+		regionalRng := rand.New(source)
+		regionalFk := faker.NewWithSeed(source)
+
+		for i := 0; i < 3; i++ {
+			secretName := fmt.Sprintf("%s-secret", regionalFk.Lorem().Word())
+			secretInfo := SecretInfo{
+				Project:     f.projectID,
+				Location:    location,
+				Name:        secretName,
+				FullPath:    fmt.Sprintf("projects/%s/locations/%s/secrets/%s", f.projectID, location, secretName),
+				CreateTime:  baseTime.Add(time.Duration(regionalRng.Int64N(int64(time.Hour * 24 * 365)))),
+				Labels:      map[string]string{"environment": "test", "residency": location},
+				Annotations: map[string]string{"description": regionalFk.Lorem().Sentence(5)},
+			}
+			secretInfo = f.withMetadata(f.withSettings(secretInfo))
+			if filter.Match(secretInfo) {
+				secrets = append(secrets, secretInfo)
+			}
+		}
+	}
+
+	for _, secretInfo := range f.createdSecrets() {
+		secretInfo = f.withMetadata(f.withSettings(secretInfo))
+		if filter.Match(secretInfo) {
+			secrets = append(secrets, secretInfo)
+		}
 	}
 	return secrets, nil
 }
@@ -196,6 +642,11 @@ func (f FakeClient) GetSecretInfo(fullPath string) (SecretInfo, error) {
 		return SecretInfo{}, fmt.Errorf("invalid secret path: %s", fullPath)
 	}
 	secretName := parts[len(parts)-1]
+	for _, secretInfo := range f.createdSecrets() {
+		if secretInfo.FullPath == fullPath {
+			return f.withMetadata(f.withSettings(secretInfo)), nil
+		}
+	}
 	
 	seed := seedFromSecretName(fullPath)
 	source := rand.NewPCG(uint64(seed), uint64(seed>>32))
@@ -230,11 +681,13 @@ func (f FakeClient) GetSecretInfo(fullPath string) (SecretInfo, error) {
 		annotations["last-rotated"] = time.Now().AddDate(0, -rng.IntN(12), -rng.IntN(30)).Format("2006-01-02")
 	}
 	
-	return SecretInfo{
+	return f.withMetadata(f.withSettings(SecretInfo{
+		Project:     f.projectID,
+		Location:    LocationOf(fullPath),
 		Name:        secretName,
 		FullPath:    fullPath,
 		CreateTime:  baseTime.Add(timeOffset),
 		Labels:      labels,
 		Annotations: annotations,
-	}, nil
+	})), nil
 }
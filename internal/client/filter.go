@@ -0,0 +1,390 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Filter is a parsed ListSecrets filter expression, evaluated locally by the
+// clients that have no API to send it to. It follows the Secret Manager
+// grammar: restrictions like name:api, labels.team=payments, labels.team:*
+// or create_time>2024-01-01, bare values, NOT or -, parentheses, and AND/OR
+// where OR binds tighter than AND.
+type Filter struct {
+	expr string
+	root filterNode
+}
+
+type filterNode interface {
+	match(secretInfo SecretInfo) bool
+}
+
+type filterAnd []filterNode
+
+func (n filterAnd) match(secretInfo SecretInfo) bool {
+	for _, child := range n {
+		if !child.match(secretInfo) {
+			return false
+		}
+	}
+	return true
+}
+
+type filterOr []filterNode
+
+func (n filterOr) match(secretInfo SecretInfo) bool {
+	for _, child := range n {
+		if child.match(secretInfo) {
+			return true
+		}
+	}
+	return false
+}
+
+type filterNot struct {
+	node filterNode
+}
+
+func (n filterNot) match(secretInfo SecretInfo) bool {
+	return !n.node.match(secretInfo)
+}
+
+// filterValue is a bare value, matched against the name and label values.
+type filterValue struct {
+	value string
+}
+
+func (n filterValue) match(secretInfo SecretInfo) bool {
+	if hasValue(secretInfo.Name, n.value) {
+		return true
+	}
+	for _, labelValue := range secretInfo.Labels {
+		if hasValue(labelValue, n.value) {
+			return true
+		}
+	}
+	return false
+}
+
+type filterName struct {
+	op    string
+	value string
+}
+
+func (n filterName) match(secretInfo SecretInfo) bool {
+	return compareString(secretInfo.Name, n.op, n.value)
+}
+
+type filterLabel struct {
+	key   string
+	op    string
+	value string
+}
+
+func (n filterLabel) match(secretInfo SecretInfo) bool {
+	if n.key == "" {
+		for key := range secretInfo.Labels {
+			if compareString(key, n.op, n.value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	labelValue, ok := secretInfo.Labels[n.key]
+	if !ok {
+		return n.op == "!="
+	}
+	return compareString(labelValue, n.op, n.value)
+}
+
+type filterCreateTime struct {
+	op   string
+	time time.Time
+}
+
+func (n filterCreateTime) match(secretInfo SecretInfo) bool {
+	created := secretInfo.CreateTime
+	switch n.op {
+	case "<":
+		return created.Before(n.time)
+	case "<=":
+		return !created.After(n.time)
+	case ">":
+		return created.After(n.time)
+	case ">=":
+		return !created.Before(n.time)
+	case "=":
+		return created.Equal(n.time)
+	case "!=":
+		return !created.Equal(n.time)
+	}
+	return false
+}
+
+// ParseFilter parses a filter expression. An empty expression matches every
+// secret.
+func ParseFilter(expr string) (*Filter, error) {
+	filter := &Filter{expr: strings.TrimSpace(expr)}
+	if filter.expr == "" {
+		return filter, nil
+	}
+
+	tokens, err := tokenizeFilter(filter.expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+	filter.root, err = p.parseSequence()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in filter", p.tokens[p.pos])
+	}
+
+	return filter, nil
+}
+
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
+
+// Match reports whether the secret is kept by the filter.
+func (f *Filter) Match(secretInfo SecretInfo) bool {
+	if f == nil || f.root == nil {
+		return true
+	}
+	return f.root.match(secretInfo)
+}
+
+func tokenizeFilter(expr string) ([]string, error) {
+	var tokens []string
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		default:
+			start := i
+			inQuotes := false
+			for i < len(runes) && (inQuotes || !(unicode.IsSpace(runes[i]) || runes[i] == '(' || runes[i] == ')')) {
+				if runes[i] == '"' {
+					inQuotes = !inQuotes
+				}
+				i++
+			}
+			if inQuotes {
+				return nil, fmt.Errorf("unterminated quote in filter")
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		}
+	}
+
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *filterParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *filterParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+// parseSequence parses factors joined by AND or plain whitespace.
+func (p *filterParser) parseSequence() (filterNode, error) {
+	var nodes filterAnd
+	for {
+		node, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+
+		if p.peek() == "AND" {
+			p.next()
+			continue
+		}
+		if p.peek() == "" || p.peek() == ")" {
+			break
+		}
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+// parseFactor parses terms joined by OR.
+func (p *filterParser) parseFactor() (filterNode, error) {
+	var nodes filterOr
+	for {
+		node, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+
+		if p.peek() != "OR" {
+			break
+		}
+		p.next()
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *filterParser) parseTerm() (filterNode, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("filter ends unexpectedly")
+	case token == "NOT":
+		p.next()
+		node, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{node}, nil
+	case strings.HasPrefix(token, "-") && len(token) > 1:
+		p.tokens[p.pos] = token[1:]
+		node, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{node}, nil
+	case token == "(":
+		p.next()
+		node, err := p.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis in filter")
+		}
+		return node, nil
+	case token == ")" || token == "AND" || token == "OR":
+		return nil, fmt.Errorf("unexpected %q in filter", token)
+	}
+
+	return parseRestriction(p.next())
+}
+
+func parseRestriction(token string) (filterNode, error) {
+	i := strings.IndexAny(token, ":=!<>")
+	if i < 0 {
+		return filterValue{unquoteFilter(token)}, nil
+	}
+
+	field := token[:i]
+	op := token[i : i+1]
+	rest := token[i+1:]
+	if len(rest) > 0 && rest[0] == '=' && op != ":" && op != "=" {
+		op += "="
+		rest = rest[1:]
+	}
+	if op == "!" {
+		return nil, fmt.Errorf("unknown operator in %q", token)
+	}
+	value := unquoteFilter(rest)
+	if value == "" {
+		return nil, fmt.Errorf("missing value in %q", token)
+	}
+
+	switch {
+	case field == "name":
+		if op != ":" && op != "=" && op != "!=" {
+			return nil, fmt.Errorf("name does not support %s", op)
+		}
+		return filterName{op: op, value: value}, nil
+	case field == "labels":
+		if op != ":" {
+			return nil, fmt.Errorf("use labels.KEY%sVALUE to compare a label", op)
+		}
+		return filterLabel{op: op, value: value}, nil
+	case strings.HasPrefix(field, "labels."):
+		if op != ":" && op != "=" && op != "!=" {
+			return nil, fmt.Errorf("labels do not support %s", op)
+		}
+		return filterLabel{key: strings.TrimPrefix(field, "labels."), op: op, value: value}, nil
+	case field == "create_time":
+		if op == ":" {
+			return nil, fmt.Errorf("create_time needs a comparison like create_time>2024-01-01")
+		}
+		t, err := parseFilterTime(value)
+		if err != nil {
+			return nil, err
+		}
+		return filterCreateTime{op: op, time: t}, nil
+	}
+
+	return nil, fmt.Errorf("unknown filter field %q", field)
+}
+
+func parseFilterTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, use 2024-01-31 or 2024-01-31T10:00:00Z", value)
+}
+
+func unquoteFilter(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// compareString applies op to s. ":" ignores case and matches when s contains
+// value; a trailing "*" makes it a prefix match and "*" alone matches anything.
+func compareString(s, op, value string) bool {
+	switch op {
+	case "=":
+		return s == value
+	case "!=":
+		return s != value
+	case ":":
+		s, value = strings.ToLower(s), strings.ToLower(value)
+		if value == "*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(value, "*"); ok {
+			return strings.HasPrefix(s, prefix)
+		}
+		return strings.Contains(s, value)
+	}
+	return false
+}
+
+func hasValue(s, value string) bool {
+	return compareString(s, ":", value)
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FilterTestSuite struct {
+	suite.Suite
+	api    SecretInfo
+	worker SecretInfo
+}
+
+func (suite *FilterTestSuite) SetupTest() {
+	suite.api = SecretInfo{
+		Name:       "payments-api-key",
+		Labels:     map[string]string{"team": "payments", "env": "prod"},
+		CreateTime: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	suite.worker = SecretInfo{
+		Name:       "search-worker-db",
+		Labels:     map[string]string{"team": "search"},
+		CreateTime: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestFilterSuite(t *testing.T) {
+	suite.Run(t, new(FilterTestSuite))
+}
+
+func (suite *FilterTestSuite) match(expr string, secretInfo SecretInfo) bool {
+	filter, err := ParseFilter(expr)
+	suite.Require().NoError(err)
+	return filter.Match(secretInfo)
+}
+
+func (suite *FilterTestSuite) TestEmptyFilter() {
+	t := suite.T()
+
+	assert.True(t, suite.match("", suite.api))
+	assert.True(t, (*Filter)(nil).Match(suite.api))
+}
+
+func (suite *FilterTestSuite) TestName() {
+	t := suite.T()
+
+	assert.True(t, suite.match("name:api", suite.api))
+	assert.True(t, suite.match("name:API", suite.api))
+	assert.False(t, suite.match("name:api", suite.worker))
+	assert.True(t, suite.match("name:search*", suite.worker))
+	assert.False(t, suite.match("name:worker*", suite.worker))
+	assert.True(t, suite.match("name=search-worker-db", suite.worker))
+	assert.False(t, suite.match("name=search", suite.worker))
+}
+
+func (suite *FilterTestSuite) TestLabels() {
+	t := suite.T()
+
+	assert.True(t, suite.match("labels.team=payments", suite.api))
+	assert.False(t, suite.match("labels.team=payments", suite.worker))
+	assert.True(t, suite.match("labels.env:*", suite.api))
+	assert.False(t, suite.match("labels.env:*", suite.worker))
+	assert.True(t, suite.match("labels.team!=payments", suite.worker))
+	assert.True(t, suite.match("labels:env", suite.api))
+	assert.False(t, suite.match("labels:env", suite.worker))
+}
+
+func (suite *FilterTestSuite) TestCreateTime() {
+	t := suite.T()
+
+	assert.True(t, suite.match("create_time>2024-01-01", suite.api))
+	assert.False(t, suite.match("create_time>2024-01-01", suite.worker))
+	assert.True(t, suite.match("create_time<=2023-06-01T00:00:00Z", suite.worker))
+}
+
+func (suite *FilterTestSuite) TestBareValue() {
+	t := suite.T()
+
+	assert.True(t, suite.match("worker", suite.worker))
+	assert.True(t, suite.match("payments", suite.api))
+	assert.False(t, suite.match("payments", suite.worker))
+}
+
+func (suite *FilterTestSuite) TestOperators() {
+	t := suite.T()
+
+	assert.True(t, suite.match("labels.team=payments AND name:api", suite.api))
+	assert.True(t, suite.match("labels.team=payments name:api", suite.api))
+	assert.False(t, suite.match("labels.team=payments AND name:worker", suite.api))
+	assert.True(t, suite.match("name:worker OR name:api", suite.api))
+	assert.True(t, suite.match("NOT labels.team=payments", suite.worker))
+	assert.True(t, suite.match("-labels.team=payments", suite.worker))
+	assert.False(t, suite.match("-name:api", suite.api))
+}
+
+func (suite *FilterTestSuite) TestPrecedence() {
+	t := suite.T()
+
+	// OR binds tighter than AND, as in the Secret Manager API.
+	assert.False(t, suite.match("name:worker AND labels.team=payments OR labels.env=prod", suite.api))
+	assert.True(t, suite.match("(name:worker AND labels.team=search) OR labels.env=prod", suite.api))
+}
+
+func (suite *FilterTestSuite) TestQuotedValue() {
+	t := suite.T()
+	secretInfo := SecretInfo{Name: "owner", Labels: map[string]string{"owner": "jane doe"}}
+
+	assert.True(t, suite.match(`labels.owner="jane doe"`, secretInfo))
+}
+
+func (suite *FilterTestSuite) TestInvalidFilter() {
+	t := suite.T()
+
+	for _, expr := range []string{
+		"(name:api",
+		"name:api)",
+		"unknown:value",
+		"name<api",
+		"create_time>yesterday",
+		"name:",
+		`name:"api`,
+		"name:api AND",
+	} {
+		_, err := ParseFilter(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func (suite *FilterTestSuite) TestString() {
+	t := suite.T()
+	filter, err := ParseFilter("  labels.team=payments  ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "labels.team=payments", filter.String())
+}
+
+func (suite *FilterTestSuite) TestFakeClientFilter() {
+	t := suite.T()
+	fake, _ := NewFakeClient("test-project")
+	all, _ := fake.Secrets()
+
+	err := fake.SetFilter("name:" + all[0].Name)
+	assert.NoError(t, err)
+	assert.Equal(t, "name:"+all[0].Name, fake.Filter())
+
+	filtered, _ := fake.Secrets()
+	assert.NotEmpty(t, filtered)
+	assert.Less(t, len(filtered), len(all))
+
+	assert.Error(t, fake.SetFilter("name:("))
+	assert.Equal(t, "name:"+all[0].Name, fake.Filter())
+}
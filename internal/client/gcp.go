@@ -6,51 +6,125 @@ import (
 	"fmt"
 	"hash/crc32"
 	"path/filepath"
+	"slices"
+	"smm/internal/search"
 	"strconv"
 	"strings"
 	"sync"
 
+	"cloud.google.com/go/iam/apiv1/iampb"
+	resourcemanager "cloud.google.com/go/resourcemanager/apiv3"
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"google.golang.org/genproto/googleapis/type/expr"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Gcp struct {
 	projectID   string
 	ctx         context.Context
 	client      *secretmanager.Client
+	regional    map[string]*secretmanager.Client
+	projects    *resourcemanager.ProjectsClient
+	projectsMu  sync.Mutex
+	locations   []string
 	secretInfos []SecretInfo
+	filter      string
 	cancel      context.CancelFunc
+	credentials Credentials
+	options     []option.ClientOption
+	identity    string
 }
 
-func NewGcp(projectID string) (*Gcp, error) {
+func NewGcp(projectID string, opts Options) (*Gcp, error) {
 	ctx, cancel := context.WithCancel(context.Background())
-	gcp := &Gcp{projectID: projectID, ctx: ctx, cancel: cancel}
+	gcp := &Gcp{projectID: projectID, filter: opts.Filter, locations: opts.Locations, credentials: opts.Credentials, ctx: ctx, cancel: cancel}
 	err := gcp.gcpConnect()
 	if err != nil {
-		cancel()
+		_ = gcp.Close()
 		return nil, fmt.Errorf("failed to connect to GCP Secret Manager: %w", err)
 	}
 
-	gcp.secretInfos, err = gcp.fetchSecretInfos()
+	gcp.secretInfos, err = gcp.fetchSecretInfos(gcp.filter)
 	return gcp, nil
 }
 
 func (g *Gcp) gcpConnect() error {
 
 	var err error
-	g.client, err = secretmanager.NewClient(g.ctx)
+	g.options, err = g.credentials.clientOptions(g.ctx)
+	if err != nil {
+		return err
+	}
+	g.identity = g.credentials.identity(g.ctx)
+
+	g.client, err = secretmanager.NewClient(g.ctx, g.options...)
 
 	if err != nil {
 		return err
 	}
+
+	// Regional secrets are only served by the endpoint of their location.
+	g.regional = map[string]*secretmanager.Client{}
+	for _, location := range g.locations {
+		endpoint := fmt.Sprintf("secretmanager.%s.rep.googleapis.com:443", location)
+		regional, err := secretmanager.NewClient(g.ctx, append(g.options, option.WithEndpoint(endpoint))...)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", endpoint, err)
+		}
+		g.regional[location] = regional
+	}
 	return nil
 }
 
+// Close stops what is still running and closes the global and regional
+// endpoints.
+func (g *Gcp) Close() error {
+	g.cancel()
+	var errs []error
+	if g.client != nil {
+		errs = append(errs, g.client.Close())
+	}
+	for _, regional := range g.regional {
+		errs = append(errs, regional.Close())
+	}
+	g.projectsMu.Lock()
+	defer g.projectsMu.Unlock()
+	if g.projects != nil {
+		errs = append(errs, g.projects.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// clientFor returns the client of the endpoint serving the resource name.
+func (g *Gcp) clientFor(name string) *secretmanager.Client {
+	if regional, ok := g.regional[LocationOf(name)]; ok {
+		return regional
+	}
+	return g.client
+}
+
+// Identity is the account the credentials of the project act as.
+func (g *Gcp) Identity() string {
+	return g.identity
+}
+
+// secretPath expands a bare secret name into the path of a global secret.
+func (g *Gcp) secretPath(secretName string) string {
+	if strings.HasPrefix(secretName, "projects/") {
+		return secretName
+	}
+	return fmt.Sprintf("projects/%s/secrets/%s", g.projectID, secretName)
+}
+
 func (g *Gcp) Secrets() ([]SecretInfo, error) {
 	if g.secretInfos == nil {
-		secretInfos, err := g.fetchSecretInfos()
+		secretInfos, err := g.fetchSecretInfos(g.filter)
 		if err != nil {
 			return nil, err
 		}
@@ -59,47 +133,80 @@ func (g *Gcp) Secrets() ([]SecretInfo, error) {
 	return g.secretInfos, nil
 }
 
-func (g *Gcp) fetchSecretInfos() ([]SecretInfo, error) {
-	listSecretsReq := &secretmanagerpb.ListSecretsRequest{
-		Parent: fmt.Sprintf("projects/%s", g.projectID),
+// SetFilter lists the secrets again with the new filter, which the API
+// evaluates. The previous filter is kept if the API rejects it.
+func (g *Gcp) SetFilter(filter string) error {
+	previous := g.filter
+	g.filter = filter
+
+	secretInfos, err := g.fetchSecretInfos(g.filter)
+	if err != nil {
+		g.filter = previous
+		return err
 	}
+	g.secretInfos = secretInfos
+	return nil
+}
 
-	listSecrets := g.client.ListSecrets(g.ctx, listSecretsReq)
+func (g *Gcp) Filter() string {
+	return g.filter
+}
 
-	var secretInfos []SecretInfo
+// AllSecrets lists the secrets again without the filter, leaving the listing
+// returned by Secrets untouched.
+func (g *Gcp) AllSecrets() ([]SecretInfo, error) {
+	return g.fetchSecretInfos("")
+}
 
-	for {
-		secretData, err := listSecrets.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, err
+// fetchSecretInfos lists the global secrets followed by the secrets of each
+// configured location that match filter.
+func (g *Gcp) fetchSecretInfos(filter string) ([]SecretInfo, error) {
+	parents := []string{fmt.Sprintf("projects/%s", g.projectID)}
+	for _, location := range g.locations {
+		parents = append(parents, fmt.Sprintf("projects/%s/locations/%s", g.projectID, location))
+	}
+
+	var secretInfos []SecretInfo
+	for _, parent := range parents {
+		listSecretsReq := &secretmanagerpb.ListSecretsRequest{
+			Parent: parent,
+			Filter: filter,
 		}
 
-		name := filepath.Base(secretData.Name)
+		listSecrets := g.clientFor(parent).ListSecrets(g.ctx, listSecretsReq)
+		for {
+			secretData, err := listSecrets.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
 
-		secretInfo := SecretInfo{
-			Name:        name,
-			FullPath:    secretData.Name,
-			CreateTime:  secretData.CreateTime.AsTime(),
-			Labels:      secretData.Labels,
-			Annotations: secretData.Annotations,
+			secretInfos = append(secretInfos, g.secretInfo(secretData))
 		}
-
-		secretInfos = append(secretInfos, secretInfo)
 	}
 
 	return secretInfos, nil
 }
 
 func (g *Gcp) GetSecretVersions(secretName string) ([]Version, error) {
+	return g.listVersions(g.ctx, secretName)
+}
+
+func (g *Gcp) listVersions(ctx context.Context, secretName string) ([]Version, error) {
+	secret, err := g.clientFor(secretName).GetSecret(ctx, &secretmanagerpb.GetSecretRequest{Name: secretName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret aliases: %w", err)
+	}
+	aliases := versionAliases(secret.VersionAliases)
+
 	req := &secretmanagerpb.ListSecretVersionsRequest{
 		Parent: fmt.Sprintf("%s", secretName),
 	}
 
 	var versions []Version
-	it := g.client.ListSecretVersions(g.ctx, req)
+	it := g.clientFor(secretName).ListSecretVersions(ctx, req)
 	for {
 		resp, err := it.Next()
 		if errors.Is(err, iterator.Done) {
@@ -119,6 +226,8 @@ func (g *Gcp) GetSecretVersions(secretName string) ([]Version, error) {
 			State:     resp.State.String(),
 			Version:   versionNumber,
 			CreatedAt: resp.CreateTime.AsTime(),
+			Aliases:   aliases[versionNumber],
+			Replicas:  versionReplicas(resp.GetReplicationStatus()),
 		}
 		versions = append(versions, version)
 	}
@@ -126,12 +235,45 @@ func (g *Gcp) GetSecretVersions(secretName string) ([]Version, error) {
 	return versions, nil
 }
 
+func (g *Gcp) SetVersionAlias(secretName, alias string, version int) error {
+	secret, err := g.clientFor(secretName).GetSecret(g.ctx, &secretmanagerpb.GetSecretRequest{Name: secretName})
+	if err != nil {
+		return fmt.Errorf("failed to get secret aliases: %w", err)
+	}
+
+	aliases := secret.VersionAliases
+	if aliases == nil {
+		aliases = map[string]int64{}
+	}
+	aliases[alias] = int64(version)
+
+	req := &secretmanagerpb.UpdateSecretRequest{
+		Secret: &secretmanagerpb.Secret{
+			Name:           secretName,
+			Etag:           secret.Etag,
+			VersionAliases: aliases,
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"version_aliases"}},
+	}
+
+	_, err = g.clientFor(secretName).UpdateSecret(g.ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to update secret aliases: %w", err)
+	}
+	log.Info().Msgf("Alias %s of %s points to version %d", alias, secretName, version)
+	return nil
+}
+
 func (g *Gcp) GetSecret(secretName string) ([]byte, error) {
+	return g.accessSecret(g.ctx, secretName)
+}
+
+func (g *Gcp) accessSecret(ctx context.Context, secretName string) ([]byte, error) {
 	accessRequest := &secretmanagerpb.AccessSecretVersionRequest{
 		Name: fmt.Sprintf("%s/versions/latest", secretName),
 	}
 
-	result, err := g.client.AccessSecretVersion(g.ctx, accessRequest)
+	result, err := g.clientFor(secretName).AccessSecretVersion(ctx, accessRequest)
 	if err != nil {
 		return nil, fmt.Errorf("failed to access secret version %q: %w", secretName, err)
 	}
@@ -140,6 +282,10 @@ func (g *Gcp) GetSecret(secretName string) ([]byte, error) {
 }
 
 func (g *Gcp) GetSecretVersion(secretName, version string) ([]byte, error) {
+	return g.accessVersion(g.ctx, secretName, version)
+}
+
+func (g *Gcp) accessVersion(ctx context.Context, secretName, version string) ([]byte, error) {
 	name := fmt.Sprintf("%s/versions/%s", secretName, version)
 	log.Info().Msgf("Fetching secret version: %s", name)
 
@@ -147,7 +293,7 @@ func (g *Gcp) GetSecretVersion(secretName, version string) ([]byte, error) {
 		Name: name,
 	}
 
-	result, err := g.client.AccessSecretVersion(g.ctx, req)
+	result, err := g.clientFor(name).AccessSecretVersion(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to access secret version: %w", err)
 	}
@@ -162,7 +308,7 @@ func (g *Gcp) GetSecretVersion(secretName, version string) ([]byte, error) {
 }
 
 func (g *Gcp) AddSecretVersion(secretName string, payload []byte) error {
-	parent := fmt.Sprintf("projects/%s/secrets/%s", g.projectID, secretName)
+	parent := g.secretPath(secretName)
 
 	crc32c := crc32.MakeTable(crc32.Castagnoli)
 	checksum := int64(crc32.Checksum(payload, crc32c))
@@ -175,7 +321,7 @@ func (g *Gcp) AddSecretVersion(secretName string, payload []byte) error {
 		},
 	}
 
-	result, err := g.client.AddSecretVersion(g.ctx, req)
+	result, err := g.clientFor(parent).AddSecretVersion(g.ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to add secret version: %w", err)
 	}
@@ -183,36 +329,28 @@ func (g *Gcp) AddSecretVersion(secretName string, payload []byte) error {
 	return nil
 }
 
-func (g *Gcp) SearchInSecrets(query string) ([]SecretInfo, error) {
-	secretInfos, err := g.fetchSecretInfos()
+func (g *Gcp) SearchInSecrets(ctx context.Context, query *search.Query, opts SearchOptions, events chan<- SearchEvent) error {
+	secretInfos, err := g.fetchSecretInfos(g.filter)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var foundSecrets []SecretInfo
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-
-	for _, secretInfo := range secretInfos {
-		wg.Add(1)
-		go func(secretInfo SecretInfo) {
-			defer wg.Done()
-			secretData, err := g.GetSecret(secretInfo.FullPath)
+	return scanSecrets(ctx, secretInfos, opts, events, func(ctx context.Context, secretInfo SecretInfo) (bool, []Version, error) {
+		if opts.AllVersions && query.NeedsPayload() {
+			versions, err := g.listVersions(ctx, secretInfo.FullPath)
 			if err != nil {
-				log.Error().Err(err).Str("secret", secretInfo.FullPath).Msg("failed to get secret during search")
-				return
-			}
-
-			if strings.Contains(string(secretData), query) {
-				mu.Lock()
-				foundSecrets = append(foundSecrets, secretInfo)
-				mu.Unlock()
+				return false, nil, err
 			}
-		}(secretInfo)
-	}
+			return matchVersions(query, secretInfo, versions, func(version Version) ([]byte, error) {
+				return g.accessVersion(ctx, secretInfo.FullPath, strconv.Itoa(version.Version))
+			})
+		}
 
-	wg.Wait()
-	return foundSecrets, nil
+		found, err := matchSecret(query, secretInfo, func() ([]byte, error) {
+			return g.accessSecret(ctx, secretInfo.FullPath)
+		})
+		return found, nil, err
+	})
 }
 
 func (g *Gcp) GetSecretInfo(fullPath string) (SecretInfo, error) {
@@ -226,20 +364,312 @@ func (g *Gcp) GetSecretInfo(fullPath string) (SecretInfo, error) {
 		Name: fullPath,
 	}
 
-	secret, err := g.client.GetSecret(g.ctx, req)
+	secret, err := g.clientFor(fullPath).GetSecret(g.ctx, req)
 	if err != nil {
 		return SecretInfo{}, fmt.Errorf("failed to get secret info: %w", err)
 	}
 
-	name := filepath.Base(secret.Name)
+	return g.secretInfo(secret), nil
+}
 
+func (g *Gcp) secretInfo(secret *secretmanagerpb.Secret) SecretInfo {
 	secretInfo := SecretInfo{
-		Name:        name,
+		Project:     g.projectID,
+		Location:    LocationOf(secret.Name),
+		Name:        filepath.Base(secret.Name),
 		FullPath:    secret.Name,
 		CreateTime:  secret.CreateTime.AsTime(),
 		Labels:      secret.Labels,
 		Annotations: secret.Annotations,
 	}
 
+	if rotation := secret.Rotation; rotation != nil {
+		if rotation.NextRotationTime != nil {
+			secretInfo.NextRotationTime = rotation.NextRotationTime.AsTime()
+		}
+		if rotation.RotationPeriod != nil {
+			secretInfo.RotationPeriod = rotation.RotationPeriod.AsDuration()
+		}
+	}
+	for _, topic := range secret.Topics {
+		secretInfo.Topics = append(secretInfo.Topics, topic.Name)
+	}
+	if expireTime := secret.GetExpireTime(); expireTime != nil {
+		secretInfo.ExpireTime = expireTime.AsTime()
+	}
+
+	secretInfo.Etag = secret.GetEtag()
+	if ttl := secret.GetVersionDestroyTtl(); ttl != nil {
+		secretInfo.VersionDestroyTTL = ttl.AsDuration()
+	}
+	if cmek := secret.GetCustomerManagedEncryption(); cmek != nil {
+		secretInfo.KmsKeys = append(secretInfo.KmsKeys, cmek.GetKmsKeyName())
+	}
+	if automatic := secret.GetReplication().GetAutomatic(); automatic != nil {
+		secretInfo.Replication = "automatic"
+		if cmek := automatic.GetCustomerManagedEncryption(); cmek != nil {
+			secretInfo.KmsKeys = append(secretInfo.KmsKeys, cmek.GetKmsKeyName())
+		}
+	} else if userManaged := secret.GetReplication().GetUserManaged(); userManaged != nil {
+		secretInfo.Replication = "user-managed"
+		for _, replica := range userManaged.GetReplicas() {
+			secretInfo.Locations = append(secretInfo.Locations, replica.GetLocation())
+			if cmek := replica.GetCustomerManagedEncryption(); cmek != nil {
+				secretInfo.KmsKeys = append(secretInfo.KmsKeys, cmek.GetKmsKeyName())
+			}
+		}
+	} else if secretInfo.Location != "" {
+		secretInfo.Replication = "regional"
+		secretInfo.Locations = []string{secretInfo.Location}
+	}
+
+	return secretInfo
+}
+
+// versionReplicas reads the replication status of a secret version.
+func versionReplicas(status *secretmanagerpb.ReplicationStatus) []Replica {
+	if automatic := status.GetAutomatic(); automatic != nil {
+		return []Replica{{KmsKeyVersion: automatic.GetCustomerManagedEncryption().GetKmsKeyVersionName()}}
+	}
+
+	var replicas []Replica
+	for _, replica := range status.GetUserManaged().GetReplicas() {
+		replicas = append(replicas, Replica{
+			Location:      replica.GetLocation(),
+			KmsKeyVersion: replica.GetCustomerManagedEncryption().GetKmsKeyVersionName(),
+		})
+	}
+	return replicas
+}
+
+// settingsSecret returns a Secret holding settings, for creating or updating
+// a secret.
+func settingsSecret(name string, settings SecretSettings) *secretmanagerpb.Secret {
+	secret := &secretmanagerpb.Secret{Name: name}
+	if !settings.NextRotationTime.IsZero() {
+		secret.Rotation = &secretmanagerpb.Rotation{NextRotationTime: timestamppb.New(settings.NextRotationTime)}
+		if settings.RotationPeriod > 0 {
+			secret.Rotation.RotationPeriod = durationpb.New(settings.RotationPeriod)
+		}
+	}
+	for _, topic := range settings.Topics {
+		secret.Topics = append(secret.Topics, &secretmanagerpb.Topic{Name: topic})
+	}
+	if !settings.ExpireTime.IsZero() {
+		secret.Expiration = &secretmanagerpb.Secret_ExpireTime{ExpireTime: timestamppb.New(settings.ExpireTime)}
+	}
+	return secret
+}
+
+func (g *Gcp) CreateSecret(secretName string, settings SecretSettings, payload []byte) (SecretInfo, error) {
+	secret := settingsSecret("", settings)
+	secret.Replication = &secretmanagerpb.Replication{
+		Replication: &secretmanagerpb.Replication_Automatic_{Automatic: &secretmanagerpb.Replication_Automatic{}},
+	}
+
+	req := &secretmanagerpb.CreateSecretRequest{
+		Parent:   fmt.Sprintf("projects/%s", g.projectID),
+		SecretId: secretName,
+		Secret:   secret,
+	}
+
+	created, err := g.client.CreateSecret(g.ctx, req)
+	if err != nil {
+		return SecretInfo{}, fmt.Errorf("failed to create secret: %w", err)
+	}
+	secretInfo := g.secretInfo(created)
+	g.secretInfos = append(g.secretInfos, secretInfo)
+
+	// A secret without its first version is deleted again rather than left
+	// behind empty.
+	if err := g.AddSecretVersion(secretName, payload); err != nil {
+		if deleteErr := g.DeleteSecret(created.Name); deleteErr != nil {
+			return SecretInfo{}, fmt.Errorf("%w; %s was created without a version and could not be deleted: %v", err, secretName, deleteErr)
+		}
+		return SecretInfo{}, err
+	}
+	log.Info().Msgf("Created secret: %s", created.Name)
 	return secretInfo, nil
 }
+
+func (g *Gcp) CreateSecretFrom(template SecretInfo) (SecretInfo, error) {
+	secret := settingsSecret("", template.Settings())
+	secret.Labels = template.Labels
+	secret.Annotations = template.Annotations
+	if template.VersionDestroyTTL > 0 {
+		secret.VersionDestroyTtl = durationpb.New(template.VersionDestroyTTL)
+	}
+
+	parent := fmt.Sprintf("projects/%s", g.projectID)
+	switch {
+	case template.Location != "":
+		// Regional secrets are replicated in their location only.
+		parent = fmt.Sprintf("projects/%s/locations/%s", g.projectID, template.Location)
+	case template.Replication == "user-managed":
+		var replicas []*secretmanagerpb.Replication_UserManaged_Replica
+		for _, location := range template.Locations {
+			replicas = append(replicas, &secretmanagerpb.Replication_UserManaged_Replica{Location: location})
+		}
+		secret.Replication = &secretmanagerpb.Replication{
+			Replication: &secretmanagerpb.Replication_UserManaged_{UserManaged: &secretmanagerpb.Replication_UserManaged{Replicas: replicas}},
+		}
+	default:
+		secret.Replication = &secretmanagerpb.Replication{
+			Replication: &secretmanagerpb.Replication_Automatic_{Automatic: &secretmanagerpb.Replication_Automatic{}},
+		}
+	}
+
+	req := &secretmanagerpb.CreateSecretRequest{
+		Parent:   parent,
+		SecretId: template.Name,
+		Secret:   secret,
+	}
+
+	created, err := g.clientFor(parent).CreateSecret(g.ctx, req)
+	if err != nil {
+		return SecretInfo{}, fmt.Errorf("failed to create secret %s: %w", template.Name, err)
+	}
+	secretInfo := g.secretInfo(created)
+	g.secretInfos = append(g.secretInfos, secretInfo)
+	log.Info().Msgf("Created secret: %s", created.Name)
+	return secretInfo, nil
+}
+
+func (g *Gcp) DeleteSecret(fullPath string) error {
+	err := g.clientFor(fullPath).DeleteSecret(g.ctx, &secretmanagerpb.DeleteSecretRequest{Name: fullPath})
+	if err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	g.secretInfos = slices.DeleteFunc(g.secretInfos, func(secretInfo SecretInfo) bool {
+		return secretInfo.FullPath == fullPath
+	})
+	log.Info().Msgf("Deleted secret: %s", fullPath)
+	return nil
+}
+
+// UpdateSecretSettings replaces the rotation, topics and expiration of a
+// secret.
+func (g *Gcp) UpdateSecretSettings(fullPath string, settings SecretSettings) error {
+	req := &secretmanagerpb.UpdateSecretRequest{
+		Secret:     settingsSecret(fullPath, settings),
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"rotation", "topics", "expire_time"}},
+	}
+
+	updated, err := g.clientFor(fullPath).UpdateSecret(g.ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to update secret settings: %w", err)
+	}
+
+	for i, secretInfo := range g.secretInfos {
+		if secretInfo.FullPath == fullPath {
+			g.secretInfos[i] = g.secretInfo(updated)
+		}
+	}
+	return nil
+}
+
+func (g *Gcp) SetLabels(fullPath string, labels map[string]string) error {
+	req := &secretmanagerpb.UpdateSecretRequest{
+		Secret:     &secretmanagerpb.Secret{Name: fullPath, Labels: labels},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	}
+
+	updated, err := g.clientFor(fullPath).UpdateSecret(g.ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to update secret labels: %w", err)
+	}
+
+	for i, secretInfo := range g.secretInfos {
+		if secretInfo.FullPath == fullPath {
+			g.secretInfos[i] = g.secretInfo(updated)
+		}
+	}
+	return nil
+}
+
+// GetIamPolicy reads the policy of a secret, asking for version 3 so that
+// conditional bindings are returned.
+func (g *Gcp) GetIamPolicy(fullPath string) (Policy, error) {
+	req := &iampb.GetIamPolicyRequest{
+		Resource: fullPath,
+		Options:  &iampb.GetPolicyOptions{RequestedPolicyVersion: 3},
+	}
+
+	result, err := g.clientFor(fullPath).GetIamPolicy(g.ctx, req)
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to get IAM policy: %w", err)
+	}
+
+	policy := Policy{Version: result.GetVersion(), Etag: result.GetEtag()}
+	for _, binding := range result.GetBindings() {
+		condition := Condition{
+			Title:       binding.GetCondition().GetTitle(),
+			Description: binding.GetCondition().GetDescription(),
+			Expression:  binding.GetCondition().GetExpression(),
+		}
+		policy.Bindings = append(policy.Bindings, Binding{Role: binding.GetRole(), Members: binding.GetMembers(), Condition: condition})
+	}
+	return policy, nil
+}
+
+func (g *Gcp) SetIamPolicy(fullPath string, policy Policy) error {
+	result := &iampb.Policy{Version: policy.Version, Etag: policy.Etag}
+	for _, binding := range policy.Bindings {
+		pbBinding := &iampb.Binding{Role: binding.Role, Members: binding.Members}
+		if binding.Condition != (Condition{}) {
+			pbBinding.Condition = &expr.Expr{
+				Title:       binding.Condition.Title,
+				Description: binding.Condition.Description,
+				Expression:  binding.Condition.Expression,
+			}
+			result.Version = 3
+		}
+		result.Bindings = append(result.Bindings, pbBinding)
+	}
+
+	_, err := g.clientFor(fullPath).SetIamPolicy(g.ctx, &iampb.SetIamPolicyRequest{Resource: fullPath, Policy: result})
+	if err != nil {
+		return fmt.Errorf("failed to set IAM policy: %w", err)
+	}
+	log.Info().Msgf("Updated IAM policy: %s", fullPath)
+	return nil
+}
+
+// projectsClient connects to Resource Manager, which is only needed to test
+// permissions on the project, on first use. Permissions are tested from
+// commands, so the connection is guarded.
+func (g *Gcp) projectsClient() (*resourcemanager.ProjectsClient, error) {
+	g.projectsMu.Lock()
+	defer g.projectsMu.Unlock()
+	if g.projects == nil {
+		projects, err := resourcemanager.NewProjectsClient(g.ctx, g.options...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to Resource Manager: %w", err)
+		}
+		g.projects = projects
+	}
+	return g.projects, nil
+}
+
+// TestIamPermissions asks Secret Manager about a secret, and Resource Manager
+// about a project.
+func (g *Gcp) TestIamPermissions(resource string, permissions []string) ([]string, error) {
+	req := &iampb.TestIamPermissionsRequest{Resource: resource, Permissions: permissions}
+
+	var result *iampb.TestIamPermissionsResponse
+	var err error
+	if strings.Contains(resource, "/secrets/") {
+		result, err = g.clientFor(resource).TestIamPermissions(g.ctx, req)
+	} else {
+		var projects *resourcemanager.ProjectsClient
+		projects, err = g.projectsClient()
+		if err != nil {
+			return nil, err
+		}
+		result, err = projects.TestIamPermissions(g.ctx, req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to test IAM permissions: %w", err)
+	}
+	return result.GetPermissions(), nil
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Policy is the IAM policy of a secret. Etag guards SetIamPolicy against
+// concurrent changes.
+type Policy struct {
+	Version  int32
+	Bindings []Binding
+	Etag     []byte
+}
+
+// Binding grants Role to Members, only while Condition holds when it is set.
+type Binding struct {
+	Role      string
+	Members   []string
+	Condition Condition
+}
+
+// Condition is a CEL expression restricting a binding. The zero Condition
+// means the binding always applies.
+type Condition struct {
+	Title       string
+	Description string
+	Expression  string
+}
+
+var memberPrefixes = []string{"user:", "serviceAccount:", "group:", "domain:", "principal:", "principalSet:"}
+
+// ValidateMember checks that member names an IAM principal, such as
+// user:ana@example.com or serviceAccount:app@p.iam.gserviceaccount.com.
+func ValidateMember(member string) error {
+	if member == "allUsers" || member == "allAuthenticatedUsers" {
+		return nil
+	}
+	for _, prefix := range memberPrefixes {
+		if strings.HasPrefix(member, prefix) && len(member) > len(prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("member must start with user:, serviceAccount:, group:, domain: or principal:")
+}
+
+// ValidateRole checks that role is a predefined or custom role name.
+func ValidateRole(role string) error {
+	if strings.HasPrefix(role, "roles/") || strings.Contains(role, "/roles/") {
+		return nil
+	}
+	return fmt.Errorf("role must look like roles/secretmanager.secretAccessor")
+}
+
+// AddMember returns a copy of the policy granting role to member without
+// condition.
+func (p Policy) AddMember(role, member string) Policy {
+	policy := p.clone()
+	for i, binding := range policy.Bindings {
+		if binding.Role == role && binding.Condition == (Condition{}) {
+			if !slices.Contains(binding.Members, member) {
+				policy.Bindings[i].Members = append(binding.Members, member)
+				sort.Strings(policy.Bindings[i].Members)
+			}
+			return policy
+		}
+	}
+
+	policy.Bindings = append(policy.Bindings, Binding{Role: role, Members: []string{member}})
+	return policy
+}
+
+// RemoveMember returns a copy of the policy without member in the binding of
+// role with the given condition, dropping the binding if it ends up empty.
+func (p Policy) RemoveMember(role, member string, condition Condition) Policy {
+	policy := p.clone()
+	var bindings []Binding
+	for _, binding := range policy.Bindings {
+		if binding.Role == role && binding.Condition == condition {
+			binding.Members = slices.DeleteFunc(binding.Members, func(m string) bool { return m == member })
+		}
+		if len(binding.Members) > 0 {
+			bindings = append(bindings, binding)
+		}
+	}
+	policy.Bindings = bindings
+	return policy
+}
+
+func (p Policy) clone() Policy {
+	policy := Policy{Version: p.Version, Etag: p.Etag}
+	for _, binding := range p.Bindings {
+		binding.Members = slices.Clone(binding.Members)
+		policy.Bindings = append(policy.Bindings, binding)
+	}
+	return policy
+}
+
+// grants lists every member of the policy as "role member [condition]".
+func (p Policy) grants() []string {
+	var grants []string
+	for _, binding := range p.Bindings {
+		for _, member := range binding.Members {
+			grant := binding.Role + " " + member
+			if binding.Condition.Expression != "" {
+				grant += " if " + binding.Condition.Expression
+			}
+			grants = append(grants, grant)
+		}
+	}
+	sort.Strings(grants)
+	return grants
+}
+
+// DiffPolicy describes the grants added to and removed from before, as lines
+// starting with "+ " or "- ".
+func DiffPolicy(before, after Policy) []string {
+	beforeGrants, afterGrants := before.grants(), after.grants()
+
+	var diff []string
+	for _, grant := range beforeGrants {
+		if !slices.Contains(afterGrants, grant) {
+			diff = append(diff, "- "+grant)
+		}
+	}
+	for _, grant := range afterGrants {
+		if !slices.Contains(beforeGrants, grant) {
+			diff = append(diff, "+ "+grant)
+		}
+	}
+	return diff
+}
+
+// Permissions checked before offering the actions that need them.
+const (
+	PermissionAccessVersion = "secretmanager.versions.access"
+	PermissionAddVersion    = "secretmanager.versions.add"
+	PermissionUpdateSecret  = "secretmanager.secrets.update"
+	PermissionSetIamPolicy  = "secretmanager.secrets.setIamPolicy"
+	PermissionCreateSecret  = "secretmanager.secrets.create"
+)
+
+// SecretPermissions are the permissions tested on a secret.
+var SecretPermissions = []string{PermissionAccessVersion, PermissionAddVersion, PermissionUpdateSecret, PermissionSetIamPolicy}
+
+// ProjectPermissions are the permissions tested on the project.
+var ProjectPermissions = []string{PermissionCreateSecret}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var conditional = Condition{Title: "Temporary", Expression: `request.time < timestamp("2030-01-01T00:00:00Z")`}
+
+func testPolicy() Policy {
+	return Policy{
+		Version: 3,
+		Etag:    []byte("etag"),
+		Bindings: []Binding{
+			{Role: "roles/secretmanager.secretAccessor", Members: []string{"user:ana@example.com"}},
+			{Role: "roles/secretmanager.secretAccessor", Members: []string{"user:bob@example.com"}, Condition: conditional},
+		},
+	}
+}
+
+func TestPolicyAddMember(t *testing.T) {
+	policy := testPolicy()
+
+	added := policy.AddMember("roles/secretmanager.secretAccessor", "group:ops@example.com")
+	assert.Equal(t, []string{"group:ops@example.com", "user:ana@example.com"}, added.Bindings[0].Members)
+	assert.Equal(t, []string{"user:ana@example.com"}, policy.Bindings[0].Members, "the original policy is not modified")
+
+	added = policy.AddMember("roles/secretmanager.viewer", "user:ana@example.com")
+	assert.Len(t, added.Bindings, 3)
+	assert.Equal(t, []byte("etag"), added.Etag)
+}
+
+func TestPolicyRemoveMember(t *testing.T) {
+	policy := testPolicy()
+
+	removed := policy.RemoveMember("roles/secretmanager.secretAccessor", "user:bob@example.com", conditional)
+	assert.Len(t, removed.Bindings, 1)
+	assert.Equal(t, "user:ana@example.com", removed.Bindings[0].Members[0])
+
+	unchanged := policy.RemoveMember("roles/secretmanager.secretAccessor", "user:bob@example.com", Condition{})
+	assert.Equal(t, policy, unchanged)
+}
+
+func TestDiffPolicy(t *testing.T) {
+	policy := testPolicy()
+	updated := policy.AddMember("roles/secretmanager.admin", "group:ops@example.com").
+		RemoveMember("roles/secretmanager.secretAccessor", "user:bob@example.com", conditional)
+
+	assert.Equal(t, []string{
+		`- roles/secretmanager.secretAccessor user:bob@example.com if request.time < timestamp("2030-01-01T00:00:00Z")`,
+		"+ roles/secretmanager.admin group:ops@example.com",
+	}, DiffPolicy(policy, updated))
+	assert.Empty(t, DiffPolicy(policy, policy))
+}
+
+func TestValidateMember(t *testing.T) {
+	assert.NoError(t, ValidateMember("user:ana@example.com"))
+	assert.NoError(t, ValidateMember("serviceAccount:app@p.iam.gserviceaccount.com"))
+	assert.NoError(t, ValidateMember("allAuthenticatedUsers"))
+	assert.Error(t, ValidateMember("ana@example.com"))
+	assert.Error(t, ValidateMember("user:"))
+
+	assert.NoError(t, ValidateRole("roles/secretmanager.secretAccessor"))
+	assert.NoError(t, ValidateRole("projects/p/roles/custom"))
+	assert.Error(t, ValidateRole("secretAccessor"))
+}
+
+func TestFakeClientIamPolicy(t *testing.T) {
+	fake, _ := NewFakeClient("test-project")
+	fullPath := "projects/test-project/secrets/api-key"
+
+	policy, err := fake.GetIamPolicy(fullPath)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, policy.Bindings)
+
+	updated := policy.AddMember("roles/secretmanager.viewer", "user:ana@example.com")
+	assert.NoError(t, fake.SetIamPolicy(fullPath, updated))
+
+	saved, _ := fake.GetIamPolicy(fullPath)
+	assert.Equal(t, updated.Bindings, saved.Bindings)
+	assert.Error(t, fake.SetIamPolicy(fullPath, updated), "the etag is stale")
+}
+
+func TestFakeClientTestIamPermissions(t *testing.T) {
+	fake, _ := NewFakeClient("test-project")
+
+	granted, err := fake.TestIamPermissions("projects/test-project", ProjectPermissions)
+	assert.NoError(t, err)
+	assert.Equal(t, ProjectPermissions, granted)
+
+	readOnly := 0
+	secrets, _ := fake.Secrets()
+	for _, secret := range secrets {
+		granted, err := fake.TestIamPermissions(secret.FullPath, SecretPermissions)
+		assert.NoError(t, err)
+		assert.Contains(t, granted, PermissionAccessVersion)
+		if len(granted) < len(SecretPermissions) {
+			readOnly++
+		}
+	}
+	assert.Positive(t, readOnly, "some fake secrets are read-only")
+	assert.Less(t, readOnly, len(secrets))
+}
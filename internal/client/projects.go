@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	resourcemanager "cloud.google.com/go/resourcemanager/apiv3"
+	"cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"google.golang.org/api/iterator"
+)
+
+// DiscoveredProject is a project the credentials can access.
+type DiscoveredProject struct {
+	ID   string
+	Name string
+	Type string
+}
+
+// ProjectDiscoverer lists the projects that can be added without typing
+// their ID.
+type ProjectDiscoverer interface {
+	DiscoverProjects() ([]DiscoveredProject, error)
+}
+
+// NewProjectDiscoverer returns the discoverer for projects of the given
+// type, searching as credentials. Any type other than "gcp" gets the fake
+// one.
+func NewProjectDiscoverer(projectType string, credentials Credentials) ProjectDiscoverer {
+	if projectType == "gcp" {
+		return GcpProjects{Credentials: credentials}
+	}
+	return FakeProjects{}
+}
+
+var projectIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+
+// ValidateProjectID checks that id is a well-formed GCP project ID.
+func ValidateProjectID(id string) error {
+	if !projectIDPattern.MatchString(id) {
+		return fmt.Errorf("%q is not a project ID: 6 to 30 lowercase letters, digits or hyphens", id)
+	}
+	return nil
+}
+
+// ValidateProjectType checks that projectType is one New knows: gcp or fake.
+func ValidateProjectType(projectType string) error {
+	switch projectType {
+	case "gcp", "fake":
+		return nil
+	}
+	return fmt.Errorf("unknown type %q, use gcp or fake", projectType)
+}
+
+// GcpProjects discovers the active projects visible to Credentials through
+// Resource Manager.
+type GcpProjects struct {
+	Credentials Credentials
+}
+
+func (p GcpProjects) DiscoverProjects() ([]DiscoveredProject, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	opts, err := p.Credentials.clientOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	projectsClient, err := resourcemanager.NewProjectsClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Resource Manager: %w", err)
+	}
+	defer projectsClient.Close()
+
+	var projects []DiscoveredProject
+	it := projectsClient.SearchProjects(ctx, &resourcemanagerpb.SearchProjectsRequest{Query: "state:ACTIVE"})
+	for {
+		project, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to search projects: %w", err)
+		}
+		projects = append(projects, DiscoveredProject{ID: project.GetProjectId(), Name: project.GetDisplayName(), Type: "gcp"})
+	}
+
+	sort.Slice(projects, func(i, j int) bool { return projects[i].ID < projects[j].ID })
+	return projects, nil
+}
+
+// FakeProjects discovers a fixed set of projects.
+type FakeProjects struct{}
+
+func (FakeProjects) DiscoverProjects() ([]DiscoveredProject, error) {
+	return []DiscoveredProject{
+		{ID: "fake-payments-dev", Name: "Payments (dev)", Type: "fake"},
+		{ID: "fake-payments-prod", Name: "Payments", Type: "fake"},
+		{ID: "fake-platform-staging", Name: "Platform staging", Type: "fake"},
+	}, nil
+}
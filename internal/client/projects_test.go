@@ -0,0 +1,33 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProjectID(t *testing.T) {
+	assert.NoError(t, ValidateProjectID("my-gcp-project-1"))
+	assert.Error(t, ValidateProjectID("my-gcp-project-"))
+	assert.Error(t, ValidateProjectID("My-Project"))
+	assert.Error(t, ValidateProjectID("abc"))
+	assert.Error(t, ValidateProjectID("1project"))
+}
+
+func TestValidateProjectType(t *testing.T) {
+	assert.NoError(t, ValidateProjectType("gcp"))
+	assert.NoError(t, ValidateProjectType("fake"))
+	assert.Error(t, ValidateProjectType(""))
+	assert.Error(t, ValidateProjectType("aws"))
+}
+
+func TestNewProjectDiscoverer(t *testing.T) {
+	assert.IsType(t, GcpProjects{}, NewProjectDiscoverer("gcp", Credentials{}))
+
+	projects, err := NewProjectDiscoverer("fake", Credentials{}).DiscoverProjects()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, projects)
+	for _, project := range projects {
+		assert.NoError(t, ValidateProjectID(project.ID))
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Protection levels of a project.
+const (
+	ProtectionNormal       = "normal"
+	ProtectionReadOnly     = "readonly"
+	ProtectionConfirmTyped = "confirm-typed"
+)
+
+// ErrReadOnly is returned by every change to a read-only project.
+var ErrReadOnly = errors.New("the project is read-only")
+
+// writePermissions are the tested permissions a read-only project never
+// grants.
+var writePermissions = []string{PermissionAddVersion, PermissionUpdateSecret, PermissionSetIamPolicy, PermissionCreateSecret}
+
+// ValidateProtection checks that level is a known protection level; empty
+// means normal.
+func ValidateProtection(level string) error {
+	switch level {
+	case "", ProtectionNormal, ProtectionReadOnly, ProtectionConfirmTyped:
+		return nil
+	}
+	return fmt.Errorf("unknown protection %q, use %s, %s or %s", level, ProtectionNormal, ProtectionReadOnly, ProtectionConfirmTyped)
+}
+
+// ReadOnly wraps a client and rejects every call that changes the project.
+type ReadOnly struct {
+	Client
+}
+
+func NewReadOnly(c Client) ReadOnly {
+	return ReadOnly{Client: c}
+}
+
+func (r ReadOnly) AddSecretVersion(secretName string, payload []byte) error {
+	return ErrReadOnly
+}
+
+func (r ReadOnly) SetVersionAlias(secretName, alias string, version int) error {
+	return ErrReadOnly
+}
+
+func (r ReadOnly) CreateSecret(secretName string, settings SecretSettings, payload []byte) (SecretInfo, error) {
+	return SecretInfo{}, ErrReadOnly
+}
+
+func (r ReadOnly) CreateSecretFrom(template SecretInfo) (SecretInfo, error) {
+	return SecretInfo{}, ErrReadOnly
+}
+
+func (r ReadOnly) DeleteSecret(fullPath string) error {
+	return ErrReadOnly
+}
+
+func (r ReadOnly) UpdateSecretSettings(fullPath string, settings SecretSettings) error {
+	return ErrReadOnly
+}
+
+func (r ReadOnly) SetLabels(fullPath string, labels map[string]string) error {
+	return ErrReadOnly
+}
+
+func (r ReadOnly) SetIamPolicy(fullPath string, policy Policy) error {
+	return ErrReadOnly
+}
+
+// TestIamPermissions drops the permissions needed to change the project, so
+// that the actions needing them are shown as denied.
+func (r ReadOnly) TestIamPermissions(resource string, permissions []string) ([]string, error) {
+	granted, err := r.Client.TestIamPermissions(resource, permissions)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(granted, func(permission string) bool {
+		return slices.Contains(writePermissions, permission)
+	}), nil
+}
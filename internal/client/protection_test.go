@@ -0,0 +1,40 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReadOnlyRejectsChanges(t *testing.T) {
+	c, err := New("test-project", "fake", Options{Protection: ProtectionReadOnly})
+	assert.NoError(t, err)
+	fullPath := "projects/test-project/secrets/api-key"
+
+	_, err = c.GetSecret(fullPath)
+	assert.NoError(t, err, "reads still work")
+
+	assert.ErrorIs(t, c.AddSecretVersion(fullPath, []byte("x")), ErrReadOnly)
+	assert.ErrorIs(t, c.SetVersionAlias(fullPath, "prod", 1), ErrReadOnly)
+	assert.ErrorIs(t, c.UpdateSecretSettings(fullPath, SecretSettings{}), ErrReadOnly)
+	assert.ErrorIs(t, c.SetLabels(fullPath, map[string]string{"team": "payments"}), ErrReadOnly)
+	assert.ErrorIs(t, c.DeleteSecret(fullPath), ErrReadOnly)
+	_, err = c.CreateSecretFrom(SecretInfo{Name: "new-secret"})
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.ErrorIs(t, c.SetIamPolicy(fullPath, Policy{}), ErrReadOnly)
+	_, err = c.CreateSecret("new-secret", SecretSettings{}, []byte("x"))
+	assert.ErrorIs(t, err, ErrReadOnly)
+
+	granted, err := c.TestIamPermissions("projects/test-project", ProjectPermissions)
+	assert.NoError(t, err)
+	assert.Empty(t, granted)
+}
+
+func TestNewProtection(t *testing.T) {
+	c, err := New("test-project", "fake", Options{Protection: ProtectionConfirmTyped})
+	assert.NoError(t, err)
+	assert.NotPanics(t, func() { _ = c.(FakeClient) })
+
+	_, err = New("test-project", "fake", Options{Protection: "read-only"})
+	assert.Error(t, err)
+}
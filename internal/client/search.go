@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"smm/internal/search"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultSearchParallelism = 8
+	maxSearchRetries         = 5
+)
+
+// searchBackoffBase is the first delay of withBackoff, a var so tests can
+// shorten it.
+var searchBackoffBase = 500 * time.Millisecond
+
+// SearchOptions tunes how a deep search walks the secrets of a project.
+type SearchOptions struct {
+	Parallelism int
+	AllVersions bool
+}
+
+// SearchEvent reports the progress of a running deep search. Match is set
+// when the scanned secret matched the query, Err when it could not be read.
+// When searching all versions, Versions lists the ones that matched.
+type SearchEvent struct {
+	Scanned  int
+	Total    int
+	Match    *SecretInfo
+	Versions []Version
+	Err      error
+}
+
+type matchFunc func(ctx context.Context, secretInfo SecretInfo) (bool, []Version, error)
+
+// scanSecrets runs match over every secret with a bounded pool of workers and
+// streams one event per scanned secret. It returns early when ctx is canceled.
+func scanSecrets(ctx context.Context, secretInfos []SecretInfo, opts SearchOptions, events chan<- SearchEvent, match matchFunc) error {
+	total := len(secretInfos)
+	if !sendEvent(ctx, events, SearchEvent{Total: total}) {
+		return ctx.Err()
+	}
+
+	workers := opts.Parallelism
+	if workers <= 0 {
+		workers = DefaultSearchParallelism
+	}
+	if workers > total {
+		workers = total
+	}
+
+	jobs := make(chan SecretInfo)
+	var scanned atomic.Int64
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for secretInfo := range jobs {
+				var found bool
+				var versions []Version
+				err := withBackoff(ctx, func() error {
+					var err error
+					found, versions, err = match(ctx, secretInfo)
+					return err
+				})
+				if err != nil && ctx.Err() == nil {
+					log.Error().Err(err).Str("secret", secretInfo.FullPath).Msg("failed to read secret during search")
+				}
+
+				event := SearchEvent{Scanned: int(scanned.Add(1)), Total: total, Err: err}
+				if err == nil && found {
+					event.Match = &secretInfo
+					event.Versions = versions
+				}
+				if !sendEvent(ctx, events, event) {
+					return
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, secretInfo := range secretInfos {
+		select {
+		case jobs <- secretInfo:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// matchSecret evaluates query against a secret, reading its payload through
+// fetch only when the query looks at values.
+func matchSecret(query *search.Query, secretInfo SecretInfo, fetch func() ([]byte, error)) (bool, error) {
+	candidate := search.Candidate{Project: secretInfo.Project, Labels: secretInfo.Labels, CreateTime: secretInfo.CreateTime}
+	if query.NeedsPayload() {
+		secretData, err := fetch()
+		if err != nil {
+			return false, err
+		}
+		candidate.Payload = secretData
+	}
+
+	return query.Match(candidate), nil
+}
+
+// matchVersions evaluates query against every enabled version of a secret and
+// returns the versions that matched.
+func matchVersions(query *search.Query, secretInfo SecretInfo, versions []Version, fetch func(Version) ([]byte, error)) (bool, []Version, error) {
+	var matched []Version
+	for _, version := range versions {
+		if !strings.EqualFold(version.State, "enabled") {
+			continue
+		}
+
+		found, err := matchSecret(query, secretInfo, func() ([]byte, error) {
+			return fetch(version)
+		})
+		if err != nil {
+			return false, nil, err
+		}
+		if found {
+			matched = append(matched, version)
+		}
+	}
+
+	return len(matched) > 0, matched, nil
+}
+
+// withBackoff retries fn with exponential backoff while the API reports that
+// the quota is exhausted.
+func withBackoff(ctx context.Context, fn func() error) error {
+	delay := searchBackoffBase
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if status.Code(err) != codes.ResourceExhausted || attempt >= maxSearchRetries {
+			return err
+		}
+
+		jitter := time.Duration(rand.Int64N(int64(delay)/2 + 1))
+		log.Warn().Err(err).Int("attempt", attempt+1).Dur("delay", delay+jitter).Msg("search throttled, retrying")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay + jitter):
+		}
+		delay *= 2
+	}
+}
+
+func sendEvent(ctx context.Context, events chan<- SearchEvent, event SearchEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func shortBackoff(t *testing.T) {
+	base := searchBackoffBase
+	searchBackoffBase = time.Millisecond
+	t.Cleanup(func() { searchBackoffBase = base })
+}
+
+func TestWithBackoffRetriesExhaustedQuota(t *testing.T) {
+	shortBackoff(t)
+
+	calls := 0
+	err := withBackoff(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return status.Error(codes.ResourceExhausted, "quota")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestWithBackoffGivesUp(t *testing.T) {
+	shortBackoff(t)
+
+	calls := 0
+	err := withBackoff(context.Background(), func() error {
+		calls++
+		return status.Error(codes.ResourceExhausted, "quota")
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, maxSearchRetries+1, calls)
+}
+
+func TestWithBackoffDoesNotRetryOtherErrors(t *testing.T) {
+	calls := 0
+	err := withBackoff(context.Background(), func() error {
+		calls++
+		return status.Error(codes.PermissionDenied, "denied")
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestWithBackoffStopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := withBackoff(ctx, func() error {
+		calls++
+		cancel()
+		return status.Error(codes.ResourceExhausted, "quota")
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
+
+func TestScanSecretsStreamsEveryResult(t *testing.T) {
+	var secretInfos []SecretInfo
+	for i := 0; i < 10; i++ {
+		secretInfos = append(secretInfos, SecretInfo{Name: fmt.Sprintf("secret-%d", i), FullPath: fmt.Sprintf("projects/p/secrets/secret-%d", i)})
+	}
+	broken := errors.New("broken")
+
+	events := make(chan SearchEvent)
+	done := make(chan error, 1)
+	go func() {
+		done <- scanSecrets(context.Background(), secretInfos, SearchOptions{Parallelism: 3}, events, func(ctx context.Context, secretInfo SecretInfo) (bool, []Version, error) {
+			switch secretInfo.Name {
+			case "secret-4":
+				return false, nil, broken
+			case "secret-2", "secret-7":
+				return true, nil, nil
+			}
+			return false, nil, nil
+		})
+		close(events)
+	}()
+
+	var received []SearchEvent
+	for event := range events {
+		received = append(received, event)
+	}
+	require.NoError(t, <-done)
+	require.Len(t, received, 11)
+	assert.Equal(t, SearchEvent{Total: 10}, received[0])
+
+	var scanned []int
+	var matches []string
+	for _, event := range received[1:] {
+		assert.Equal(t, 10, event.Total)
+		scanned = append(scanned, event.Scanned)
+		if event.Match != nil {
+			matches = append(matches, event.Match.Name)
+		}
+		if event.Err != nil {
+			assert.ErrorIs(t, event.Err, broken)
+			assert.Nil(t, event.Match)
+		}
+	}
+	sort.Ints(scanned)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, scanned)
+	sort.Strings(matches)
+	assert.Equal(t, []string{"secret-2", "secret-7"}, matches)
+}
+
+func TestScanSecretsStopsWhenCanceled(t *testing.T) {
+	secretInfos := make([]SecretInfo, 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan SearchEvent)
+	done := make(chan error, 1)
+	go func() {
+		done <- scanSecrets(ctx, secretInfos, SearchOptions{Parallelism: 2}, events, func(context.Context, SecretInfo) (bool, []Version, error) {
+			return false, nil, nil
+		})
+	}()
+
+	<-events
+	<-events
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("scanSecrets kept running after the context was canceled")
+	}
+}
+
+func TestFakeSearchVersionsListLikeTheAPI(t *testing.T) {
+	fake, _ := NewFakeClient("test-project")
+	secretName := "projects/test-project/secrets/api-key"
+	listed, _ := fake.GetSecretVersions(secretName)
+
+	versions, err := fake.searchVersions(secretName)
+	require.NoError(t, err)
+	require.Len(t, versions, len(listed))
+	assert.Equal(t, listed[len(listed)-1].Version, versions[0].Version, "newest first")
+	for _, version := range versions {
+		assert.Equal(t, secretName, version.FullPath)
+		assert.Equal(t, "api-key", version.Name)
+	}
+}
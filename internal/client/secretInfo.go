@@ -1,11 +1,59 @@
 package client
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 type SecretInfo struct {
+	Project     string
+	Location    string // empty for global secrets
 	Name        string
 	FullPath    string
 	CreateTime  time.Time
 	Labels      map[string]string
 	Annotations map[string]string
+
+	NextRotationTime time.Time
+	RotationPeriod   time.Duration
+	Topics           []string
+	ExpireTime       time.Time
+
+	// Replication is "automatic", "user-managed" or, for secrets stored in
+	// a single location, "regional". Locations lists where it is stored.
+	Replication       string
+	Locations         []string
+	KmsKeys           []string
+	Etag              string
+	VersionDestroyTTL time.Duration
+}
+
+// LocationOf returns the location of a regional resource name such as
+// projects/p/locations/europe-west1/secrets/s, or "" for a global one.
+func LocationOf(name string) string {
+	parts := strings.Split(name, "/")
+	if len(parts) >= 4 && parts[0] == "projects" && parts[2] == "locations" {
+		return parts[3]
+	}
+	return ""
+}
+
+// Reference returns the sm:// reference of the secret at fullPath, such as
+// sm://p/s#3, pinned to version unless it is zero. Regional secrets keep
+// their location, as in sm://p/locations/europe-west1/s.
+func Reference(fullPath string, version int) string {
+	reference := "sm://" + strings.Replace(strings.TrimPrefix(fullPath, "projects/"), "/secrets/", "/", 1)
+	if version > 0 {
+		reference += fmt.Sprintf("#%d", version)
+	}
+	return reference
+}
+
+// ConsoleURL links to the secret in the Google Cloud console.
+func (s SecretInfo) ConsoleURL() string {
+	if s.Location != "" {
+		return fmt.Sprintf("https://console.cloud.google.com/security/secret-manager/regional/%s/secret/%s/versions?project=%s", s.Location, s.Name, s.Project)
+	}
+	return fmt.Sprintf("https://console.cloud.google.com/security/secret-manager/secret/%s/versions?project=%s", s.Name, s.Project)
 }
\ No newline at end of file
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationOf(t *testing.T) {
+	assert.Equal(t, "europe-west1", LocationOf("projects/p/locations/europe-west1/secrets/s"))
+	assert.Equal(t, "europe-west1", LocationOf("projects/p/locations/europe-west1/secrets/s/versions/2"))
+	assert.Equal(t, "", LocationOf("projects/p/secrets/s"))
+	assert.Equal(t, "", LocationOf("s"))
+}
+
+func TestConsoleURL(t *testing.T) {
+	global := SecretInfo{Project: "p", Name: "s"}
+	regional := SecretInfo{Project: "p", Location: "europe-west1", Name: "s"}
+
+	assert.Equal(t, "https://console.cloud.google.com/security/secret-manager/secret/s/versions?project=p", global.ConsoleURL())
+	assert.Contains(t, regional.ConsoleURL(), "/regional/europe-west1/secret/s/")
+}
+
+func TestFakeClientRegionalSecrets(t *testing.T) {
+	global, _ := New("test-project", "fake", Options{})
+	regional, _ := New("test-project", "fake", Options{Locations: []string{"europe-west1"}})
+
+	globalSecrets, _ := global.Secrets()
+	secrets, _ := regional.Secrets()
+
+	assert.Equal(t, globalSecrets, secrets[:len(globalSecrets)])
+	assert.Len(t, secrets, len(globalSecrets)+3)
+	for _, secretInfo := range secrets[len(globalSecrets):] {
+		assert.Equal(t, "europe-west1", secretInfo.Location)
+		assert.Equal(t, "europe-west1", LocationOf(secretInfo.FullPath))
+		assert.Equal(t, "regional", secretInfo.Replication)
+
+		info, err := regional.GetSecretInfo(secretInfo.FullPath)
+		assert.NoError(t, err)
+		assert.Equal(t, "europe-west1", info.Location)
+	}
+}
+
+func TestReference(t *testing.T) {
+	assert.Equal(t, "sm://p/s", Reference("projects/p/secrets/s", 0))
+	assert.Equal(t, "sm://p/s#3", Reference("projects/p/secrets/s", 3))
+	assert.Equal(t, "sm://p/locations/europe-west1/s#2", Reference("projects/p/locations/europe-west1/secrets/s", 2))
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SecretSettings are the secret fields edited from the settings document.
+// A zero NextRotationTime means the secret does not rotate and a zero
+// ExpireTime that it never expires.
+type SecretSettings struct {
+	NextRotationTime time.Time
+	RotationPeriod   time.Duration
+	Topics           []string
+	ExpireTime       time.Time
+}
+
+// settingsDocument is the YAML form of SecretSettings. TTL is only read, as
+// a shorthand for an expire_time relative to now.
+type settingsDocument struct {
+	NextRotationTime string   `yaml:"next_rotation_time"`
+	RotationPeriod   string   `yaml:"rotation_period"`
+	Topics           []string `yaml:"topics"`
+	ExpireTime       string   `yaml:"expire_time"`
+	TTL              string   `yaml:"ttl"`
+}
+
+var (
+	topicRegex      = regexp.MustCompile(`^projects/[^/]+/topics/[^/]+$`)
+	secretNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,255}$`)
+	labelKeyRegex   = regexp.MustCompile(`^[\p{Ll}\p{Lo}][\p{Ll}\p{Lo}\p{N}_-]{0,62}$`)
+	labelValueRegex = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{N}_-]{0,63}$`)
+)
+
+// Settings returns the editable settings of the secret.
+func (s SecretInfo) Settings() SecretSettings {
+	return SecretSettings{
+		NextRotationTime: s.NextRotationTime,
+		RotationPeriod:   s.RotationPeriod,
+		Topics:           s.Topics,
+		ExpireTime:       s.ExpireTime,
+	}
+}
+
+// ExpiresWithin reports whether the secret expires before now+window. An
+// expired secret that has not been deleted yet also counts.
+func (s SecretInfo) ExpiresWithin(now time.Time, window time.Duration) bool {
+	return !s.ExpireTime.IsZero() && s.ExpireTime.Before(now.Add(window))
+}
+
+// ValidateSecretName checks a secret ID against the rules of Secret Manager.
+func ValidateSecretName(name string) error {
+	if !secretNameRegex.MatchString(name) {
+		return fmt.Errorf("name may only contain letters, digits, - and _")
+	}
+	return nil
+}
+
+// ParseLabels reads labels written as team=payments,env=dev, checking them
+// against the rules of Secret Manager. An empty string has no labels.
+func ParseLabels(s string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !labelKeyRegex.MatchString(key) {
+			return nil, fmt.Errorf("label key %q must start with a lowercase letter and hold only lowercase letters, digits, - and _", key)
+		}
+		if !labelValueRegex.MatchString(value) {
+			return nil, fmt.Errorf("label value %q may only hold lowercase letters, digits, - and _", value)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// RotationDue reports whether the next rotation of the secret is at or
+// before now.
+func (s SecretInfo) RotationDue(now time.Time) bool {
+	return !s.NextRotationTime.IsZero() && !now.Before(s.NextRotationTime)
+}
+
+// FormatSettings renders settings as the YAML document edited by the user.
+func FormatSettings(secretName string, settings SecretSettings) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Settings of %s\n", secretName)
+	b.WriteString("# next_rotation_time: RFC 3339 time of the next rotation, empty to disable rotation\n")
+	b.WriteString("# rotation_period: time between rotations, e.g. 30d or 12h, empty for a single rotation\n")
+	b.WriteString("# topics: Pub/Sub topics notified of changes, as projects/PROJECT/topics/TOPIC\n")
+	b.WriteString("# expire_time: RFC 3339 time when the secret is deleted, empty to keep it\n")
+	b.WriteString("# ttl: set instead of expire_time to delete the secret after e.g. 7d or 12h from now\n")
+
+	nextRotation := ""
+	if !settings.NextRotationTime.IsZero() {
+		nextRotation = settings.NextRotationTime.UTC().Format(time.RFC3339)
+	}
+	fmt.Fprintf(&b, "next_rotation_time: %s\n", nextRotation)
+	fmt.Fprintf(&b, "rotation_period: %s\n", FormatPeriod(settings.RotationPeriod))
+
+	if len(settings.Topics) == 0 {
+		b.WriteString("topics: []\n")
+	} else {
+		b.WriteString("topics:\n")
+		for _, topic := range settings.Topics {
+			fmt.Fprintf(&b, "  - %s\n", topic)
+		}
+	}
+
+	expireTime := ""
+	if !settings.ExpireTime.IsZero() {
+		expireTime = settings.ExpireTime.UTC().Format(time.RFC3339)
+	}
+	fmt.Fprintf(&b, "expire_time: %s\n", expireTime)
+	b.WriteString("ttl:\n")
+
+	return b.Bytes()
+}
+
+// ParseSettings reads a settings document written by FormatSettings and
+// checks it against the rules of Secret Manager.
+func ParseSettings(data []byte) (SecretSettings, error) {
+	var document settingsDocument
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return SecretSettings{}, fmt.Errorf("invalid settings: %w", err)
+	}
+
+	var settings SecretSettings
+	if value := strings.TrimSpace(document.NextRotationTime); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return SecretSettings{}, fmt.Errorf("invalid next_rotation_time %q, use 2025-01-31T10:00:00Z", value)
+		}
+		settings.NextRotationTime = t
+	}
+
+	if value := strings.TrimSpace(document.RotationPeriod); value != "" {
+		period, err := ParsePeriod(value)
+		if err != nil {
+			return SecretSettings{}, err
+		}
+		if period < time.Hour {
+			return SecretSettings{}, fmt.Errorf("rotation_period must be at least 1h")
+		}
+		settings.RotationPeriod = period
+	}
+
+	for _, topic := range document.Topics {
+		topic = strings.TrimSpace(topic)
+		if !topicRegex.MatchString(topic) {
+			return SecretSettings{}, fmt.Errorf("invalid topic %q, use projects/PROJECT/topics/TOPIC", topic)
+		}
+		settings.Topics = append(settings.Topics, topic)
+	}
+
+	if value := strings.TrimSpace(document.ExpireTime); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return SecretSettings{}, fmt.Errorf("invalid expire_time %q, use 2025-01-31T10:00:00Z", value)
+		}
+		settings.ExpireTime = t
+	}
+	if value := strings.TrimSpace(document.TTL); value != "" {
+		ttl, err := ParsePeriod(value)
+		if err != nil {
+			return SecretSettings{}, err
+		}
+		settings.ExpireTime = time.Now().Add(ttl).Truncate(time.Second)
+	}
+
+	if settings.RotationPeriod > 0 && settings.NextRotationTime.IsZero() {
+		return SecretSettings{}, fmt.Errorf("rotation_period needs a next_rotation_time")
+	}
+	if !settings.NextRotationTime.IsZero() && len(settings.Topics) == 0 {
+		return SecretSettings{}, fmt.Errorf("rotation needs at least one topic to notify")
+	}
+
+	return settings, nil
+}
+
+// FormatPeriod writes whole days as "30d" and anything else as a duration.
+func FormatPeriod(period time.Duration) string {
+	switch {
+	case period == 0:
+		return ""
+	case period%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", period/(24*time.Hour))
+	default:
+		return period.String()
+	}
+}
+
+// ParsePeriod reads a period written as days ("30d") or as a duration
+// ("12h", "90m").
+func ParsePeriod(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if period, err := time.ParseDuration(value); err == nil && period > 0 {
+		return period, nil
+	}
+	return 0, fmt.Errorf("invalid period %q, use 30d or 12h", value)
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettingsRoundTrip(t *testing.T) {
+	settings := SecretSettings{
+		NextRotationTime: time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC),
+		RotationPeriod:   30 * 24 * time.Hour,
+		Topics:           []string{"projects/p/topics/rotation", "projects/p/topics/audit"},
+		ExpireTime:       time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	document := FormatSettings("api-key", settings)
+	parsed, err := ParseSettings(document)
+
+	assert.NoError(t, err)
+	assert.Equal(t, settings, parsed)
+	assert.Contains(t, string(document), "# Settings of api-key")
+	assert.Contains(t, string(document), "rotation_period: 30d")
+}
+
+func TestSettingsEmpty(t *testing.T) {
+	parsed, err := ParseSettings(FormatSettings("api-key", SecretSettings{}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, SecretSettings{}, parsed)
+}
+
+func TestParseSettings_Invalid(t *testing.T) {
+	for _, document := range []string{
+		"next_rotation_time: tomorrow\ntopics: [projects/p/topics/t]",
+		"next_rotation_time: 2025-02-01T10:00:00Z\nrotation_period: 10m\ntopics: [projects/p/topics/t]",
+		"rotation_period: 30d\ntopics: [projects/p/topics/t]",
+		"next_rotation_time: 2025-02-01T10:00:00Z\ntopics: []",
+		"topics: [my-topic]",
+		"topics: [",
+		"expire_time: next week",
+		"ttl: forever",
+	} {
+		_, err := ParseSettings([]byte(document))
+		assert.Error(t, err, document)
+	}
+}
+
+func TestPeriod(t *testing.T) {
+	period, err := ParsePeriod("7d")
+	assert.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, period)
+
+	period, err = ParsePeriod("90m")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, period)
+
+	_, err = ParsePeriod("-1d")
+	assert.Error(t, err)
+
+	assert.Equal(t, "2d", FormatPeriod(48*time.Hour))
+	assert.Equal(t, "36h0m0s", FormatPeriod(36*time.Hour))
+	assert.Equal(t, "", FormatPeriod(0))
+}
+
+func TestParseSettings_TTL(t *testing.T) {
+	before := time.Now()
+	parsed, err := ParseSettings([]byte("expire_time: 2025-06-01T00:00:00Z\nttl: 7d\n"))
+
+	assert.NoError(t, err)
+	assert.WithinDuration(t, before.Add(7*24*time.Hour), parsed.ExpireTime, 2*time.Second)
+}
+
+func TestExpiresWithin(t *testing.T) {
+	now := time.Now()
+	week := 7 * 24 * time.Hour
+
+	assert.False(t, SecretInfo{}.ExpiresWithin(now, week))
+	assert.True(t, SecretInfo{ExpireTime: now.Add(-time.Hour)}.ExpiresWithin(now, week))
+	assert.True(t, SecretInfo{ExpireTime: now.Add(48 * time.Hour)}.ExpiresWithin(now, week))
+	assert.False(t, SecretInfo{ExpireTime: now.Add(2 * week)}.ExpiresWithin(now, week))
+}
+
+func TestValidateSecretName(t *testing.T) {
+	assert.NoError(t, ValidateSecretName("load-test_token2"))
+	assert.Error(t, ValidateSecretName(""))
+	assert.Error(t, ValidateSecretName("bad name"))
+	assert.Error(t, ValidateSecretName("path/name"))
+}
+
+func TestRotationDue(t *testing.T) {
+	now := time.Now()
+
+	assert.False(t, SecretInfo{}.RotationDue(now))
+	assert.True(t, SecretInfo{NextRotationTime: now.Add(-time.Hour)}.RotationDue(now))
+	assert.False(t, SecretInfo{NextRotationTime: now.Add(time.Hour)}.RotationDue(now))
+}
+
+func TestFakeClientUpdateSettings(t *testing.T) {
+	fake, _ := NewFakeClient("test-project")
+	secrets, _ := fake.Secrets()
+	fullPath := secrets[0].FullPath
+	settings := SecretSettings{
+		NextRotationTime: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		Topics:           []string{"projects/test-project/topics/t"},
+	}
+
+	err := fake.UpdateSecretSettings(fullPath, settings)
+	assert.NoError(t, err)
+
+	secretInfo, _ := fake.GetSecretInfo(fullPath)
+	assert.Equal(t, settings, secretInfo.Settings())
+	secrets, _ = fake.Secrets()
+	assert.Equal(t, settings, secrets[0].Settings())
+}
+
+func TestFakeClientCreateSecret(t *testing.T) {
+	fake, _ := NewFakeClient("test-project")
+	settings := SecretSettings{ExpireTime: time.Now().Add(time.Hour).Truncate(time.Second)}
+
+	created, err := fake.CreateSecret("contractor-token", settings, []byte("s3cr3t"))
+	assert.NoError(t, err)
+	assert.Equal(t, "projects/test-project/secrets/contractor-token", created.FullPath)
+	assert.Equal(t, settings.ExpireTime, created.ExpireTime)
+
+	secrets, _ := fake.Secrets()
+	assert.Equal(t, created.FullPath, secrets[len(secrets)-1].FullPath)
+	data, _ := fake.GetSecret(created.FullPath)
+	assert.Equal(t, []byte("s3cr3t"), data)
+	secretInfo, _ := fake.GetSecretInfo(created.FullPath)
+	assert.Equal(t, settings, secretInfo.Settings())
+
+	_, err = fake.CreateSecret("contractor-token", SecretSettings{}, []byte("again"))
+	assert.Error(t, err)
+}
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels(" team=payments, env=dev,owner= ")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "env": "dev", "owner": ""}, labels)
+
+	labels, err = ParseLabels("")
+	assert.NoError(t, err)
+	assert.Empty(t, labels)
+
+	_, err = ParseLabels("Team=payments")
+	assert.ErrorContains(t, err, "label key")
+	_, err = ParseLabels("team=Payments")
+	assert.ErrorContains(t, err, "label value")
+}
+
+func TestFakeClientSetLabels(t *testing.T) {
+	fake, _ := NewFakeClient("test-project")
+	secrets, _ := fake.Secrets()
+	fullPath := secrets[0].FullPath
+
+	assert.NoError(t, fake.SetLabels(fullPath, map[string]string{"team": "payments"}))
+
+	secretInfo, _ := fake.GetSecretInfo(fullPath)
+	assert.Equal(t, map[string]string{"team": "payments"}, secretInfo.Labels)
+	secrets, _ = fake.Secrets()
+	assert.Equal(t, map[string]string{"team": "payments"}, secrets[0].Labels)
+}
+
+func TestFakeClientCreateSecretFrom(t *testing.T) {
+	fake, _ := NewFakeClient("test-project")
+	template := SecretInfo{
+		Name:        "db-creds",
+		Location:    "europe-west1",
+		Labels:      map[string]string{"team": "payments"},
+		Annotations: map[string]string{"owner": "ana"},
+		ExpireTime:  time.Now().Add(time.Hour).Truncate(time.Second),
+	}
+
+	created, err := fake.CreateSecretFrom(template)
+	assert.NoError(t, err)
+	assert.Equal(t, "projects/test-project/locations/europe-west1/secrets/db-creds", created.FullPath)
+
+	assert.NoError(t, fake.AddSecretVersion(created.FullPath, []byte("v1")))
+	data, _ := fake.GetSecret(created.FullPath)
+	assert.Equal(t, []byte("v1"), data)
+	secretInfo, _ := fake.GetSecretInfo(created.FullPath)
+	assert.Equal(t, template.Labels, secretInfo.Labels)
+	assert.Equal(t, template.Annotations, secretInfo.Annotations)
+	assert.Equal(t, template.ExpireTime, secretInfo.ExpireTime)
+
+	_, err = fake.CreateSecretFrom(template)
+	assert.Error(t, err)
+
+	assert.NoError(t, fake.DeleteSecret(created.FullPath))
+	secretInfos, _ := fake.Secrets()
+	for _, secretInfo := range secretInfos {
+		assert.NotEqual(t, created.FullPath, secretInfo.FullPath)
+	}
+	assert.Error(t, fake.DeleteSecret(created.FullPath))
+	_, err = fake.CreateSecretFrom(template)
+	assert.NoError(t, err)
+}
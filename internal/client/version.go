@@ -1,6 +1,14 @@
 package client
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var aliasRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
 
 type Version struct {
 	Name      string
@@ -8,4 +16,69 @@ type Version struct {
 	Version   int
 	FullPath  string
 	CreatedAt time.Time
+	Aliases   []string
+	// Replicas is the replication status of the version: a single replica
+	// without location when replicated automatically.
+	Replicas []Replica
+}
+
+// Replica is where a version is stored and the Cloud KMS key version that
+// encrypts it, empty when Google manages the key.
+type Replica struct {
+	Location      string
+	KmsKeyVersion string
+}
+
+// LatestVersion returns the highest version number, or 0 without versions.
+// It does not rely on the order of versions, which is newest first from the
+// API and oldest first from the fake client.
+func LatestVersion(versions []Version) int {
+	latest := 0
+	for _, version := range versions {
+		latest = max(latest, version.Version)
+	}
+	return latest
+}
+
+// LatestEnabled returns the highest enabled version number, or 0 if every
+// version is disabled or destroyed. The state is compared ignoring case, as
+// the fake client lists it in lower case.
+func LatestEnabled(versions []Version) int {
+	latest := 0
+	for _, version := range versions {
+		if strings.EqualFold(version.State, "enabled") && version.Version > latest {
+			latest = version.Version
+		}
+	}
+	return latest
+}
+
+// versionAliases inverts the alias map of a secret into the sorted aliases of
+// each version.
+func versionAliases(aliases map[string]int64) map[int][]string {
+	byVersion := map[int][]string{}
+	for alias, version := range aliases {
+		byVersion[int(version)] = append(byVersion[int(version)], alias)
+	}
+	for _, names := range byVersion {
+		sort.Strings(names)
+	}
+	return byVersion
+}
+
+// ValidateAlias checks an alias name against the rules of Secret Manager: a
+// letter followed by letters, digits, dashes or underscores, other than the
+// reserved "latest" and "NEW".
+func ValidateAlias(alias string) error {
+	switch {
+	case alias == "":
+		return fmt.Errorf("alias is empty")
+	case len(alias) > 63:
+		return fmt.Errorf("alias is longer than 63 characters")
+	case alias == "latest" || alias == "NEW":
+		return fmt.Errorf("%s is reserved", alias)
+	case !aliasRegex.MatchString(alias):
+		return fmt.Errorf("alias must start with a letter and only contain letters, digits, - and _")
+	}
+	return nil
 }
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAlias(t *testing.T) {
+	for _, alias := range []string{"prod", "canary-2", "Stable_v1"} {
+		assert.NoError(t, ValidateAlias(alias), alias)
+	}
+	for _, alias := range []string{"", "latest", "NEW", "7", "2fast", "with space", "dot.ted"} {
+		assert.Error(t, ValidateAlias(alias), alias)
+	}
+}
+
+func TestVersionAliases(t *testing.T) {
+	aliases := versionAliases(map[string]int64{"prod": 7, "canary": 7, "staging": 5})
+
+	assert.Equal(t, []string{"canary", "prod"}, aliases[7])
+	assert.Equal(t, []string{"staging"}, aliases[5])
+	assert.Empty(t, aliases[1])
+}
+
+func TestFakeClientVersionAlias(t *testing.T) {
+	fake, _ := NewFakeClient("test-project")
+	secretName := "projects/test-project/secrets/api-key"
+	versions, _ := fake.GetSecretVersions(secretName)
+	oldest := versions[0].Version
+
+	err := fake.SetVersionAlias(secretName, "prod", oldest)
+	assert.NoError(t, err)
+
+	versions, _ = fake.GetSecretVersions(secretName)
+	assert.Equal(t, []string{"prod"}, versions[0].Aliases)
+
+	byAlias, err := fake.GetSecretVersion(secretName, "prod")
+	assert.NoError(t, err)
+	byNumber, _ := fake.GetSecretVersion(secretName, "1")
+	assert.Equal(t, byNumber, byAlias)
+
+	_, err = fake.GetSecretVersion(secretName, "missing")
+	assert.Error(t, err)
+}
+
+func TestLatestVersion(t *testing.T) {
+	assert.Equal(t, 0, LatestVersion(nil))
+	assert.Equal(t, 3, LatestVersion([]Version{{Version: 3}, {Version: 2}, {Version: 1}}))
+	assert.Equal(t, 3, LatestVersion([]Version{{Version: 1}, {Version: 2}, {Version: 3}}))
+
+	fake, _ := NewFakeClient("test-project")
+	versions, _ := fake.GetSecretVersions("projects/test-project/secrets/api-key")
+	assert.Equal(t, len(versions), LatestVersion(versions))
+}
+
+func TestLatestEnabled(t *testing.T) {
+	versions := []Version{{Version: 3, State: "DESTROYED"}, {Version: 2, State: "ENABLED"}, {Version: 1, State: "ENABLED"}}
+
+	assert.Equal(t, 2, LatestEnabled(versions))
+	assert.Equal(t, 0, LatestEnabled([]Version{{Version: 1, State: "DISABLED"}}))
+	assert.Equal(t, 1, LatestEnabled([]Version{{Version: 1, State: "enabled"}}))
+}
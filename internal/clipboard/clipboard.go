@@ -0,0 +1,70 @@
+package clipboard
+
+import (
+	"time"
+)
+
+// Clipboard copies through a provider and clears what it copied once
+// clearAfter has passed, unless something else was copied in between.
+type Clipboard struct {
+	provider   Provider
+	clearAfter time.Duration
+	copied     string
+	copiedAt   time.Time
+	generation int
+}
+
+// New returns a clipboard copying with provider. A zero clearAfter never
+// clears it.
+func New(provider Provider, clearAfter time.Duration) *Clipboard {
+	return &Clipboard{provider: provider, clearAfter: clearAfter}
+}
+
+func (c *Clipboard) Name() string {
+	return c.provider.Name()
+}
+
+// Copy copies text and returns the generation identifying this copy in
+// Remaining.
+func (c *Clipboard) Copy(text string, now time.Time) (int, error) {
+	if err := c.provider.Copy(text); err != nil {
+		return 0, err
+	}
+	c.generation++
+	c.copied = text
+	c.copiedAt = now
+	return c.generation, nil
+}
+
+// Remaining returns how long until the copy of generation is cleared. It
+// reports false when that copy is no longer waiting to be cleared.
+func (c *Clipboard) Remaining(generation int, now time.Time) (time.Duration, bool) {
+	if c.clearAfter <= 0 || c.copied == "" || generation != c.generation {
+		return 0, false
+	}
+	return max(c.copiedAt.Add(c.clearAfter).Sub(now), 0), true
+}
+
+// Pending reports whether the last copy is still waiting to be cleared.
+func (c *Clipboard) Pending() bool {
+	return c.clearAfter > 0 && c.copied != ""
+}
+
+// Clear empties the clipboard if it still holds the last copy, reporting
+// whether it did. A provider that cannot read the clipboard back, like OSC
+// 52, cannot tell the copy apart from text copied after it elsewhere, so
+// it is always emptied: losing that text is preferred to leaving a secret
+// in the clipboard.
+func (c *Clipboard) Clear() (bool, error) {
+	if c.copied == "" {
+		return false, nil
+	}
+	copied := c.copied
+	c.copied = ""
+	c.generation++
+
+	if text, err := c.provider.Paste(); err == nil && text != copied {
+		return false, nil
+	}
+	return true, c.provider.Copy("")
+}
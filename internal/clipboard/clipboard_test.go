@@ -0,0 +1,188 @@
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProvider struct {
+	text     string
+	canPaste bool
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) Copy(text string) error {
+	p.text = text
+	return nil
+}
+
+func (p *fakeProvider) Paste() (string, error) {
+	if !p.canPaste {
+		return "", ErrCannotPaste
+	}
+	return p.text, nil
+}
+
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func testEnv(vars map[string]string, installed ...string) Env {
+	return Env{
+		Getenv: func(key string) string { return vars[key] },
+		LookPath: func(file string) (string, error) {
+			for _, name := range installed {
+				if name == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", errors.New("not found")
+		},
+		GOOS: "linux",
+	}
+}
+
+func TestDetectAuto(t *testing.T) {
+	cases := []struct {
+		env  Env
+		want string
+	}{
+		{testEnv(map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, "wl-copy", "xclip"), "wl-copy"},
+		{testEnv(map[string]string{"DISPLAY": ":0"}, "xsel"), "xsel"},
+		{testEnv(map[string]string{"TMUX": "/tmp/tmux"}, "tmux", "xclip"), "tmux"},
+		{testEnv(map[string]string{}), "osc52"},
+	}
+
+	for _, c := range cases {
+		provider, err := Detect(Auto, c.env)
+		assert.NoError(t, err)
+		assert.Equal(t, c.want, provider.Name())
+	}
+}
+
+func TestDetectConfigured(t *testing.T) {
+	provider, err := Detect("osc52", testEnv(map[string]string{"DISPLAY": ":0"}, "xclip"))
+	assert.NoError(t, err)
+	assert.Equal(t, "osc52", provider.Name())
+
+	_, err = Detect("xclip", testEnv(map[string]string{}))
+	assert.ErrorContains(t, err, "not installed")
+
+	_, err = Detect("clippy", testEnv(map[string]string{}))
+	assert.ErrorContains(t, err, "unknown clipboard provider")
+}
+
+func TestCommandProvider(t *testing.T) {
+	var ran []string
+	var input string
+	original := runCommand
+	runCommand = func(stdin string, name string, args ...string) (string, error) {
+		ran = append([]string{name}, args...)
+		input = stdin
+		return "pasted", nil
+	}
+	defer func() { runCommand = original }()
+
+	provider, err := Detect("xclip", testEnv(map[string]string{}, "xclip"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, provider.Copy("secret"))
+	assert.Equal(t, []string{"xclip", "-selection", "clipboard"}, ran)
+	assert.Equal(t, "secret", input)
+
+	text, err := provider.Paste()
+	assert.NoError(t, err)
+	assert.Equal(t, "pasted", text)
+}
+
+func TestOSC52Provider(t *testing.T) {
+	var out bytes.Buffer
+	open := func() (io.WriteCloser, error) { return nopCloser{&out}, nil }
+
+	assert.NoError(t, osc52Provider{open: open}.Copy("hi"))
+	assert.Equal(t, "\x1b]52;c;aGk=\x07", out.String())
+
+	out.Reset()
+	assert.NoError(t, osc52Provider{tmux: true, open: open}.Copy("hi"))
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;aGk=\x07\x1b\\", out.String())
+
+	_, err := osc52Provider{open: open}.Paste()
+	assert.ErrorIs(t, err, ErrCannotPaste)
+}
+
+func TestClipboardClearsAfterDelay(t *testing.T) {
+	provider := &fakeProvider{canPaste: true}
+	c := New(provider, 30*time.Second)
+	now := time.Now()
+
+	generation, err := c.Copy("secret", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", provider.text)
+
+	assert.True(t, c.Pending())
+	remaining, pending := c.Remaining(generation, now.Add(10*time.Second))
+	assert.True(t, pending)
+	assert.Equal(t, 20*time.Second, remaining)
+
+	remaining, _ = c.Remaining(generation, now.Add(time.Minute))
+	assert.Zero(t, remaining)
+
+	cleared, err := c.Clear()
+	assert.NoError(t, err)
+	assert.True(t, cleared)
+	assert.Empty(t, provider.text)
+
+	_, pending = c.Remaining(generation, now)
+	assert.False(t, pending)
+	assert.False(t, c.Pending())
+}
+
+func TestClipboardKeepsOverwrittenText(t *testing.T) {
+	provider := &fakeProvider{canPaste: true}
+	c := New(provider, 30*time.Second)
+
+	_, err := c.Copy("secret", time.Now())
+	assert.NoError(t, err)
+	provider.text = "copied elsewhere"
+
+	cleared, err := c.Clear()
+	assert.NoError(t, err)
+	assert.False(t, cleared)
+	assert.Equal(t, "copied elsewhere", provider.text)
+}
+
+func TestClipboardClearsWhenItCannotPaste(t *testing.T) {
+	provider := &fakeProvider{}
+	c := New(provider, 30*time.Second)
+
+	_, err := c.Copy("secret", time.Now())
+	assert.NoError(t, err)
+
+	cleared, err := c.Clear()
+	assert.NoError(t, err)
+	assert.True(t, cleared)
+	assert.Empty(t, provider.text)
+}
+
+func TestClipboardWithoutDelay(t *testing.T) {
+	c := New(&fakeProvider{canPaste: true}, 0)
+
+	generation, err := c.Copy("secret", time.Now())
+	assert.NoError(t, err)
+
+	_, pending := c.Remaining(generation, time.Now())
+	assert.False(t, pending)
+	assert.False(t, c.Pending())
+}
@@ -0,0 +1,177 @@
+// Package clipboard copies text through the clipboard tool available in the
+// session, or through the terminal with OSC 52, and clears what it copied
+// after a delay unless it was overwritten in between.
+package clipboard
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrCannotPaste is returned by providers that can only write the clipboard.
+var ErrCannotPaste = errors.New("the clipboard cannot be read back")
+
+// Provider writes and, when it can, reads the clipboard.
+type Provider interface {
+	Name() string
+	Copy(text string) error
+	Paste() (string, error)
+}
+
+// Auto picks the first available provider, in the order of Names.
+const Auto = "auto"
+
+// Names lists the providers that can be configured, in the order they are
+// tried automatically.
+var Names = []string{"wl-copy", "xclip", "xsel", "pbcopy", "tmux", "osc52"}
+
+// Env is what providers are detected from, replaceable in tests.
+type Env struct {
+	Getenv   func(key string) string
+	LookPath func(file string) (string, error)
+	GOOS     string
+}
+
+func DefaultEnv() Env {
+	return Env{Getenv: os.Getenv, LookPath: exec.LookPath, GOOS: runtime.GOOS}
+}
+
+// Detect returns the provider called name or, for Auto or an empty name, the
+// first one available in env. OSC 52 is always available as a last resort.
+func Detect(name string, env Env) (Provider, error) {
+	if name == "" || name == Auto {
+		for _, candidate := range Names {
+			if available(candidate, env) {
+				return newProvider(candidate, env), nil
+			}
+		}
+	}
+
+	provider := newProvider(name, env)
+	if provider == nil {
+		return nil, fmt.Errorf("unknown clipboard provider %q, use %s or %s", name, strings.Join(Names, ", "), Auto)
+	}
+	if command, ok := provider.(commandProvider); ok {
+		if _, err := env.LookPath(command.copy[0]); err != nil {
+			return nil, fmt.Errorf("clipboard provider %s is not installed: %w", name, err)
+		}
+	}
+	return provider, nil
+}
+
+// available reports whether the provider can reach a clipboard in env.
+func available(name string, env Env) bool {
+	installed := func(file string) bool {
+		_, err := env.LookPath(file)
+		return err == nil
+	}
+
+	switch name {
+	case "wl-copy":
+		return env.Getenv("WAYLAND_DISPLAY") != "" && installed("wl-copy")
+	case "xclip", "xsel":
+		return env.Getenv("DISPLAY") != "" && installed(name)
+	case "pbcopy":
+		return env.GOOS == "darwin" && installed("pbcopy")
+	case "tmux":
+		return env.Getenv("TMUX") != "" && installed("tmux")
+	case "osc52":
+		return true
+	}
+	return false
+}
+
+func newProvider(name string, env Env) Provider {
+	switch name {
+	case "wl-copy":
+		return commandProvider{name: name, copy: []string{"wl-copy"}, paste: []string{"wl-paste", "--no-newline"}}
+	case "xclip":
+		return commandProvider{name: name, copy: []string{"xclip", "-selection", "clipboard"}, paste: []string{"xclip", "-selection", "clipboard", "-o"}}
+	case "xsel":
+		return commandProvider{name: name, copy: []string{"xsel", "--clipboard", "--input"}, paste: []string{"xsel", "--clipboard", "--output"}}
+	case "pbcopy":
+		return commandProvider{name: name, copy: []string{"pbcopy"}, paste: []string{"pbpaste"}}
+	case "tmux":
+		// -w also sets the terminal's clipboard when tmux's set-clipboard
+		// option allows it.
+		return commandProvider{name: name, copy: []string{"tmux", "load-buffer", "-w", "-"}, paste: []string{"tmux", "save-buffer", "-"}}
+	case "osc52":
+		return osc52Provider{tmux: env.Getenv("TMUX") != "", open: openTerminal}
+	}
+	return nil
+}
+
+// commandProvider runs a clipboard tool, writing the text to its input.
+type commandProvider struct {
+	name  string
+	copy  []string
+	paste []string
+}
+
+// runCommand runs a command with input on its stdin and returns its output.
+// It is a variable so that tests do not need the clipboard tools.
+var runCommand = func(input string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w", name, err)
+	}
+	return string(out), nil
+}
+
+func (p commandProvider) Name() string {
+	return p.name
+}
+
+func (p commandProvider) Copy(text string) error {
+	_, err := runCommand(text, p.copy[0], p.copy[1:]...)
+	return err
+}
+
+func (p commandProvider) Paste() (string, error) {
+	return runCommand("", p.paste[0], p.paste[1:]...)
+}
+
+// osc52Provider asks the terminal to set its clipboard with an OSC 52 escape
+// sequence, which also works over SSH. Inside tmux it is wrapped to pass
+// through to the outer terminal.
+type osc52Provider struct {
+	tmux bool
+	open func() (io.WriteCloser, error)
+}
+
+// openTerminal opens the controlling terminal, written to directly so that
+// the sequence does not mix with the interface being rendered.
+func openTerminal() (io.WriteCloser, error) {
+	return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+}
+
+func (p osc52Provider) Name() string {
+	return "osc52"
+}
+
+func (p osc52Provider) Copy(text string) error {
+	sequence := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+	if p.tmux {
+		sequence = "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+
+	terminal, err := p.open()
+	if err != nil {
+		return fmt.Errorf("failed to open the terminal: %w", err)
+	}
+	defer terminal.Close()
+	_, err = io.WriteString(terminal, sequence)
+	return err
+}
+
+func (p osc52Provider) Paste() (string, error) {
+	return "", ErrCannotPaste
+}
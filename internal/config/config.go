@@ -3,19 +3,46 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
 
 type Project struct {
-	ID   string `yaml:"id" json:"id"`
-	Type string `yaml:"type" json:"type"`
+	ID           string   `yaml:"id" json:"id"`
+	Type         string   `yaml:"type" json:"type"`
+	Filter       string   `yaml:"filter,omitempty" json:"filter,omitempty"`
+	Locations    []string `yaml:"locations,omitempty" json:"locations,omitempty"`
+	Protection   string   `yaml:"protection,omitempty" json:"protection,omitempty"`
+	Name         string   `yaml:"name,omitempty" json:"name,omitempty"`
+	Env          string   `yaml:"env,omitempty" json:"env,omitempty"`
+	Color        string   `yaml:"color,omitempty" json:"color,omitempty"`
+	LastUsed     string   `yaml:"lastUsed,omitempty" json:"lastUsed,omitempty"`
+	Credentials  string   `yaml:"credentials,omitempty" json:"credentials,omitempty"`
+	GcloudConfig string   `yaml:"gcloudConfig,omitempty" json:"gcloudConfig,omitempty"`
+	Impersonate  string   `yaml:"impersonate,omitempty" json:"impersonate,omitempty"`
+	Delegates    []string `yaml:"delegates,omitempty" json:"delegates,omitempty"`
+	Mask         *bool    `yaml:"mask,omitempty" json:"mask,omitempty"`
+	MetadataOnly *bool    `yaml:"metadataOnly,omitempty" json:"metadataOnly,omitempty"`
+}
+
+// LastUsedTime returns when the project was last opened, the zero time if
+// never.
+func (p Project) LastUsedTime() time.Time {
+	lastUsed, _ := time.Parse(time.RFC3339, p.LastUsed)
+	return lastUsed
+}
+
+// FilePath returns the path of the config file.
+func FilePath() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "smm", "config.yaml")
 }
 
 func Load() error {
-	configPath := filepath.Join(os.Getenv("HOME"), ".config", "smm")
-	configFile := filepath.Join(configPath, "config.yaml")
+	configFile := FilePath()
+	configPath := filepath.Dir(configFile)
 
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		err = os.MkdirAll(configPath, 0700)
@@ -29,6 +56,13 @@ func Load() error {
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath(configPath)
 	viper.SetDefault("projects", []Project{})
+	viper.SetDefault("searchParallelism", 8)
+	viper.SetDefault("expiryWarningDays", 7)
+	viper.SetDefault("mask", true)
+	viper.SetDefault("lockAfter", "10m")
+	viper.SetDefault("lockOnBlur", true)
+	viper.SetDefault("clipboard", "auto")
+	viper.SetDefault("clipboardClearAfter", "30s")
 
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
 		err = viper.WriteConfigAs(configFile)
@@ -74,6 +108,11 @@ func GetProjectIDs() []string {
 }
 
 func AddProjectID(projectId string) {
+	AddProject(Project{ID: projectId, Type: "gcp"})
+}
+
+// AddProject configures a project unless one with the same ID exists.
+func AddProject(added Project) {
 	var projects []Project
 	err := viper.UnmarshalKey("projects", &projects)
 	if err != nil {
@@ -81,12 +120,12 @@ func AddProjectID(projectId string) {
 	}
 
 	for _, project := range projects {
-		if project.ID == projectId {
+		if project.ID == added.ID {
 			return
 		}
 	}
 
-	projects = append(projects, Project{ID: projectId, Type: "gcp"})
+	projects = append(projects, added)
 	viper.Set("projects", projects)
 }
 
@@ -105,6 +144,238 @@ func GetTypeByProjectId(projectId string) string {
 	return "gcp"
 }
 
+// GetProject returns the configured project, or one with only its ID when
+// it is not configured.
+func GetProject(projectId string) Project {
+	var projects []Project
+	err := viper.UnmarshalKey("projects", &projects)
+	if err != nil {
+		return Project{ID: projectId}
+	}
+
+	for _, project := range projects {
+		if project.ID == projectId {
+			return project
+		}
+	}
+	return Project{ID: projectId}
+}
+
+// GetProjects returns every configured project.
+func GetProjects() []Project {
+	var projects []Project
+	err := viper.UnmarshalKey("projects", &projects)
+	if err != nil {
+		return []Project{}
+	}
+	return projects
+}
+
+// GetFilterByProjectId returns the ListSecrets filter saved for the project.
+func GetFilterByProjectId(projectId string) string {
+	var projects []Project
+	err := viper.UnmarshalKey("projects", &projects)
+	if err != nil {
+		return ""
+	}
+
+	for _, project := range projects {
+		if project.ID == projectId {
+			return project.Filter
+		}
+	}
+	return ""
+}
+
+// GetLocationsByProjectId returns the regions whose regional secrets are
+// listed for the project.
+func GetLocationsByProjectId(projectId string) []string {
+	var projects []Project
+	err := viper.UnmarshalKey("projects", &projects)
+	if err != nil {
+		return nil
+	}
+
+	for _, project := range projects {
+		if project.ID == projectId {
+			return project.Locations
+		}
+	}
+	return nil
+}
+
+// GetProtectionByProjectId returns the protection level of the project,
+// empty for the normal one.
+func GetProtectionByProjectId(projectId string) string {
+	var projects []Project
+	err := viper.UnmarshalKey("projects", &projects)
+	if err != nil {
+		return ""
+	}
+
+	for _, project := range projects {
+		if project.ID == projectId {
+			return project.Protection
+		}
+	}
+	return ""
+}
+
+// GetMaskByProjectId reports whether the values of the project's secrets are
+// masked, the project's setting overriding the global one.
+func GetMaskByProjectId(projectId string) bool {
+	if project := GetProject(projectId); project.Mask != nil {
+		return *project.Mask
+	}
+	return viper.GetBool("mask")
+}
+
+// GetMetadataOnlyByProjectId reports whether browsing the project shows only
+// the metadata of secrets, the project's setting overriding the global one.
+func GetMetadataOnlyByProjectId(projectId string) bool {
+	if project := GetProject(projectId); project.MetadataOnly != nil {
+		return *project.MetadataOnly
+	}
+	return viper.GetBool("metadataOnly")
+}
+
+// ExpandHome replaces a leading ~ of path with the home directory.
+func ExpandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		return filepath.Join(os.Getenv("HOME"), path[1:])
+	}
+	return path
+}
+
+// SetFilterByProjectId saves the ListSecrets filter of a configured project.
+func SetFilterByProjectId(projectId, filter string) {
+	var projects []Project
+	err := viper.UnmarshalKey("projects", &projects)
+	if err != nil {
+		return
+	}
+
+	for i, project := range projects {
+		if project.ID == projectId {
+			projects[i].Filter = filter
+			viper.Set("projects", projects)
+			return
+		}
+	}
+}
+
+// UpdateProject replaces the configured project with the same ID.
+func UpdateProject(updated Project) {
+	var projects []Project
+	err := viper.UnmarshalKey("projects", &projects)
+	if err != nil {
+		return
+	}
+
+	for i, project := range projects {
+		if project.ID == updated.ID {
+			projects[i] = updated
+			viper.Set("projects", projects)
+			return
+		}
+	}
+}
+
+// RemoveProject forgets a configured project, and clears it as the selected
+// or default project.
+func RemoveProject(projectId string) {
+	var projects []Project
+	err := viper.UnmarshalKey("projects", &projects)
+	if err != nil {
+		return
+	}
+
+	var kept []Project
+	for _, project := range projects {
+		if project.ID != projectId {
+			kept = append(kept, project)
+		}
+	}
+	if kept == nil {
+		kept = []Project{}
+	}
+	viper.Set("projects", kept)
+
+	if GetSelectedProjectId() == projectId {
+		SetSelectedProject("")
+	}
+	if GetDefaultProjectId() == projectId {
+		SetDefaultProjectId("")
+	}
+}
+
+// TouchProject records that the project was opened at t.
+func TouchProject(projectId string, t time.Time) {
+	project := GetProject(projectId)
+	project.LastUsed = t.UTC().Format(time.RFC3339)
+	UpdateProject(project)
+}
+
+// GetDefaultProjectId returns the project opened on start, before the last
+// selected one.
+func GetDefaultProjectId() string {
+	return viper.GetString("defaultProject")
+}
+
+// ResolveProjectId returns projectId or, when it is empty, the default
+// project and then the last selected one.
+func ResolveProjectId(projectId string) string {
+	if projectId == "" {
+		projectId = GetDefaultProjectId()
+	}
+	if projectId == "" {
+		projectId = GetSelectedProjectId()
+	}
+	return projectId
+}
+
+func SetDefaultProjectId(projectId string) {
+	viper.Set("defaultProject", projectId)
+}
+
 func GetLogPath() string {
 	return viper.GetString("logPath")
 }
+
+func GetSearchParallelism() int {
+	return viper.GetInt("searchParallelism")
+}
+
+func GetExpiryWarningDays() int {
+	return viper.GetInt("expiryWarningDays")
+}
+
+// GetLockAfter returns how long smm may stay idle before it locks, zero when
+// it never locks for being idle.
+func GetLockAfter() time.Duration {
+	return viper.GetDuration("lockAfter")
+}
+
+// GetLockOnBlur reports whether smm locks as soon as the terminal loses
+// focus.
+func GetLockOnBlur() bool {
+	return viper.GetBool("lockOnBlur")
+}
+
+// GetClipboardProvider returns the clipboard provider to copy with, "auto"
+// to detect it.
+func GetClipboardProvider() string {
+	return viper.GetString("clipboard")
+}
+
+// GetClipboardClearAfter returns how long a copied secret stays on the
+// clipboard, zero to keep it.
+func GetClipboardClearAfter() time.Duration {
+	return viper.GetDuration("clipboardClearAfter")
+}
+
+// GetUnlockCommand returns the command that must succeed to unlock smm,
+// empty when a keypress is enough.
+func GetUnlockCommand() []string {
+	return strings.Fields(viper.GetString("unlockCommand"))
+}
@@ -0,0 +1,370 @@
+// Package doctor diagnoses the setup of smm: config file, logging, editor,
+// clipboard and the credentials, reachability and permissions of each
+// configured project.
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"smm/internal/bootstrap"
+	"smm/internal/client"
+	"smm/internal/clipboard"
+	"smm/internal/config"
+	"strings"
+
+	"golang.org/x/sys/unix"
+	"gopkg.in/yaml.v3"
+)
+
+type Status int
+
+const (
+	Pass Status = iota
+	Warn
+	Fail
+)
+
+// Check is the outcome of one diagnostic, with a hint to fix it unless it
+// passed.
+type Check struct {
+	Name   string
+	Status Status
+	Detail string
+	Hint   string
+}
+
+// Section groups the checks of the setup or of a project.
+type Section struct {
+	Title  string
+	Checks []Check
+}
+
+type Report struct {
+	Sections []Section
+}
+
+// Failed reports whether any check failed.
+func (r Report) Failed() bool {
+	for _, section := range r.Sections {
+		for _, check := range section.Checks {
+			if check.Status == Fail {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Environment is what the checks inspect, replaceable in tests.
+type Environment struct {
+	ConfigFile string
+	LogPath    string
+	Editor     string
+	LookPath   func(file string) (string, error)
+	Clipboard  clipboard.Provider
+	// ClipboardErr is why the configured clipboard provider is unusable.
+	ClipboardErr error
+	Projects     []config.Project
+	NewClient    func(project config.Project) (client.Client, error)
+}
+
+// projectPermissions are tested on each project, the first two being
+// needed to browse it at all.
+var projectPermissions = []string{
+	"secretmanager.secrets.list",
+	client.PermissionAccessVersion,
+	client.PermissionAddVersion,
+	client.PermissionCreateSecret,
+	client.PermissionUpdateSecret,
+	client.PermissionSetIamPolicy,
+}
+
+// DefaultEnvironment inspects the real config, terminal tools and projects.
+// The config must be loaded before.
+func DefaultEnvironment() Environment {
+	provider, err := clipboard.Detect(config.GetClipboardProvider(), clipboard.DefaultEnv())
+	return Environment{
+		ConfigFile:   config.FilePath(),
+		LogPath:      config.GetLogPath(),
+		Editor:       os.Getenv("EDITOR"),
+		LookPath:     exec.LookPath,
+		Clipboard:    provider,
+		ClipboardErr: err,
+		Projects:     config.GetProjects(),
+		NewClient: func(project config.Project) (client.Client, error) {
+			return client.New(project.ID, project.Type, bootstrap.ClientOptions(project.ID))
+		},
+	}
+}
+
+// Main loads the config if it is valid, runs every check and prints the
+// report to w, returning the exit code.
+func Main(w io.Writer) int {
+	if CheckConfigFile(config.FilePath()).Status == Pass {
+		_ = config.Load()
+	}
+
+	report := Run(DefaultEnvironment())
+	Print(w, report)
+	if report.Failed() {
+		return 1
+	}
+	return 0
+}
+
+// Run performs every check of env.
+func Run(env Environment) Report {
+	setup := Section{Title: "Setup", Checks: []Check{
+		CheckConfigFile(env.ConfigFile),
+		CheckLogPath(env.LogPath),
+		CheckEditor(env.Editor, env.LookPath),
+		CheckClipboard(env.Clipboard, env.ClipboardErr),
+	}}
+
+	report := Report{Sections: []Section{setup}}
+	if len(env.Projects) == 0 {
+		report.Sections[0].Checks = append(report.Sections[0].Checks, Check{
+			Name:   "projects",
+			Status: Warn,
+			Detail: "no project configured",
+			Hint:   "run smm and press p, or add one under projects in the config",
+		})
+	}
+	for _, project := range env.Projects {
+		report.Sections = append(report.Sections, CheckProject(project, env.NewClient))
+	}
+	return report
+}
+
+func CheckConfigFile(path string) Check {
+	check := Check{Name: "config file", Detail: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		check.Status = Warn
+		check.Detail = path + " does not exist"
+		check.Hint = "it is created on the first run of smm"
+		return check
+	}
+	if err != nil {
+		check.Status = Fail
+		check.Detail = err.Error()
+		check.Hint = "chmod 600 " + path
+		return check
+	}
+
+	var document map[string]any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		check.Status = Fail
+		check.Detail = err.Error()
+		check.Hint = "fix the YAML syntax of " + path
+		return check
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		check.Status = Fail
+		check.Detail = "not writable: " + err.Error()
+		check.Hint = "chmod 600 " + path + ", smm saves projects and filters in it"
+		return check
+	}
+	f.Close()
+	return check
+}
+
+func CheckLogPath(path string) Check {
+	check := Check{Name: "log path", Detail: path}
+	if path == "" {
+		check.Status = Warn
+		check.Detail = "logging is disabled"
+		check.Hint = "set logPath in the config to keep the errors smm reports"
+		return check
+	}
+
+	// The file is only created by smm itself, so a missing one is checked
+	// through its directory.
+	target := path
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		target = filepath.Dir(path)
+	}
+	if err := unix.Access(target, unix.W_OK); err != nil {
+		check.Status = Fail
+		check.Detail = fmt.Sprintf("%s: %v", target, err)
+		check.Hint = "make the directory of " + path + " writable or choose another logPath"
+	}
+	return check
+}
+
+// CheckEditor finds the editor secrets are opened with, vim when $EDITOR is
+// empty.
+func CheckEditor(editor string, lookPath func(string) (string, error)) Check {
+	check := Check{Name: "editor"}
+	if editor == "" {
+		editor = "vim"
+		check.Detail = "$EDITOR is not set, using "
+	}
+	if strings.ContainsAny(editor, " \t") {
+		check.Status = Fail
+		check.Detail = fmt.Sprintf("$EDITOR=%q has arguments", editor)
+		check.Hint = "set $EDITOR to a single command, e.g. a script running: code --wait \"$@\""
+		return check
+	}
+
+	path, err := lookPath(editor)
+	if err != nil {
+		check.Status = Fail
+		check.Detail += err.Error()
+		check.Hint = "install " + editor + " or set $EDITOR to an installed editor"
+		return check
+	}
+	check.Detail += path
+	return check
+}
+
+// CheckClipboard copies a probe with the provider and reads it back,
+// restoring what the clipboard held before. A provider that cannot read the
+// clipboard is not probed, so as not to overwrite it.
+func CheckClipboard(provider clipboard.Provider, detectErr error) Check {
+	check := Check{Name: "clipboard"}
+	const probe = "smm doctor clipboard probe"
+
+	if detectErr != nil {
+		check.Status = Fail
+		check.Detail = detectErr.Error()
+		check.Hint = "install it, or set clipboard to auto in the config"
+		return check
+	}
+
+	previous, pasteErr := provider.Paste()
+	if errors.Is(pasteErr, clipboard.ErrCannotPaste) {
+		check.Status = Warn
+		check.Detail = provider.Name() + " copies through the terminal and cannot be checked"
+		check.Hint = "use a terminal supporting OSC 52; in tmux, set-clipboard on"
+		return check
+	}
+	if pasteErr != nil {
+		check.Status = Warn
+		check.Detail = provider.Name() + " cannot read the clipboard, so it was not probed: " + pasteErr.Error()
+		check.Hint = "copying may still work; set clipboard in the config to choose another provider"
+		return check
+	}
+	if err := provider.Copy(probe); err != nil {
+		check.Status = Fail
+		check.Detail = provider.Name() + ": " + err.Error()
+		check.Hint = "install wl-clipboard on Wayland or xclip on X11, or set clipboard to osc52 over SSH"
+		return check
+	}
+	pasted, err := provider.Paste()
+	_ = provider.Copy(previous)
+	if err != nil || pasted != probe {
+		check.Status = Warn
+		check.Detail = "text copied with " + provider.Name() + " could not be read back"
+		check.Hint = "copying may still work; set clipboard in the config to choose another provider"
+		return check
+	}
+	check.Detail = "copy and paste work with " + provider.Name()
+	return check
+}
+
+// CheckProject resolves the credentials of project and tests that its
+// secrets can be listed and which permissions are granted.
+func CheckProject(project config.Project, newClient func(config.Project) (client.Client, error)) Section {
+	section := Section{Title: "Project " + project.ID}
+
+	c, err := newClient(project)
+	if err != nil {
+		section.Checks = append(section.Checks, Check{Name: "credentials", Status: Fail, Detail: err.Error(), Hint: authHint(err)})
+		return section
+	}
+	defer c.Close()
+	section.Checks = append(section.Checks, Check{Name: "credentials", Detail: "as " + c.Identity()})
+
+	secrets, err := c.AllSecrets()
+	if err != nil {
+		section.Checks = append(section.Checks, Check{Name: "API", Status: Fail, Detail: err.Error(), Hint: authHint(err)})
+		return section
+	}
+	detail := fmt.Sprintf("%d secrets listed", len(secrets))
+	if filter := c.Filter(); filter != "" {
+		if filtered, err := c.Secrets(); err == nil {
+			detail += fmt.Sprintf(", %d match the saved filter %s", len(filtered), filter)
+		}
+	}
+	section.Checks = append(section.Checks, Check{Name: "API", Detail: detail})
+
+	// A read-only project is not expected to be changed, so only the
+	// permissions to browse it are checked.
+	permissions := projectPermissions
+	if project.Protection == client.ProtectionReadOnly {
+		permissions = projectPermissions[:2]
+	}
+	granted, err := c.TestIamPermissions("projects/"+project.ID, permissions)
+	if err != nil {
+		section.Checks = append(section.Checks, Check{Name: "permissions", Status: Warn, Detail: err.Error(), Hint: "actions are offered without checking permissions"})
+		return section
+	}
+	var missing []string
+	for _, permission := range permissions {
+		if !slices.Contains(granted, permission) {
+			missing = append(missing, permission)
+		}
+	}
+
+	check := Check{Name: "permissions", Detail: "all granted on the project"}
+	if project.Protection == client.ProtectionReadOnly {
+		check.Detail = "read access granted on the read-only project"
+	}
+	switch {
+	case slices.Contains(missing, projectPermissions[0]) || slices.Contains(missing, projectPermissions[1]):
+		check.Status = Fail
+		check.Detail = "missing " + strings.Join(missing, ", ")
+		check.Hint = "grant roles/secretmanager.viewer and roles/secretmanager.secretAccessor"
+	case len(missing) > 0:
+		check.Status = Warn
+		check.Detail = "missing " + strings.Join(missing, ", ")
+		check.Hint = "changes may still be allowed on single secrets; roles/secretmanager.admin grants them all"
+	}
+	section.Checks = append(section.Checks, check)
+	return section
+}
+
+// authHint suggests a fix for a client error.
+func authHint(err error) string {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "could not find default credentials"):
+		return "run gcloud auth application-default login, or set gcloudConfig or credentials for the project"
+	case strings.Contains(message, "impersonate"):
+		return "grant roles/iam.serviceAccountTokenCreator on the service account"
+	case strings.Contains(message, "PermissionDenied"), strings.Contains(message, "permission"):
+		return "grant the account roles/secretmanager.viewer on the project"
+	case strings.Contains(message, "Unavailable"), strings.Contains(message, "deadline"):
+		return "check the network and proxy settings"
+	}
+	return "check the project ID and the credentials configured for it"
+}
+
+var statusMarks = map[Status]string{Pass: "✔", Warn: "!", Fail: "✖"}
+
+// Print writes the report with a line per check and the hint below the
+// ones that did not pass.
+func Print(w io.Writer, report Report) {
+	for i, section := range report.Sections {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, section.Title)
+		for _, check := range section.Checks {
+			fmt.Fprintf(w, "  %s %-12s %s\n", statusMarks[check.Status], check.Name, check.Detail)
+			if check.Status != Pass && check.Hint != "" {
+				fmt.Fprintf(w, "    → %s\n", check.Hint)
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"smm/internal/client"
+	"smm/internal/clipboard"
+	"smm/internal/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClipboard struct {
+	text     string
+	copyErr  error
+	pasteErr error
+}
+
+func (c *fakeClipboard) Name() string {
+	return "fake"
+}
+
+func (c *fakeClipboard) Copy(s string) error {
+	if c.copyErr != nil {
+		return c.copyErr
+	}
+	c.text = s
+	return nil
+}
+
+func (c *fakeClipboard) Paste() (string, error) {
+	return c.text, c.pasteErr
+}
+
+func TestCheckConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+
+	assert.Equal(t, Warn, CheckConfigFile(path).Status)
+
+	assert.NoError(t, os.WriteFile(path, []byte("projects: []\n"), 0600))
+	assert.Equal(t, Pass, CheckConfigFile(path).Status)
+
+	assert.NoError(t, os.WriteFile(path, []byte("projects: [\n"), 0600))
+	check := CheckConfigFile(path)
+	assert.Equal(t, Fail, check.Status)
+	assert.Contains(t, check.Hint, "YAML")
+}
+
+func TestCheckLogPath(t *testing.T) {
+	assert.Equal(t, Warn, CheckLogPath("").Status)
+	logPath := filepath.Join(t.TempDir(), "smm.log")
+	assert.Equal(t, Pass, CheckLogPath(logPath).Status)
+	assert.NoFileExists(t, logPath)
+	assert.NoError(t, os.WriteFile(logPath, nil, 0600))
+	assert.Equal(t, Pass, CheckLogPath(logPath).Status)
+	assert.Equal(t, Fail, CheckLogPath(filepath.Join(t.TempDir(), "missing", "smm.log")).Status)
+}
+
+func TestCheckEditor(t *testing.T) {
+	found := func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	missing := func(file string) (string, error) { return "", errors.New("not found") }
+
+	check := CheckEditor("", found)
+	assert.Equal(t, Pass, check.Status)
+	assert.Contains(t, check.Detail, "/usr/bin/vim")
+
+	assert.Equal(t, Fail, CheckEditor("code --wait", found).Status)
+	assert.Equal(t, Fail, CheckEditor("nano", missing).Status)
+}
+
+func TestCheckClipboard(t *testing.T) {
+	c := &fakeClipboard{text: "before"}
+	assert.Equal(t, Pass, CheckClipboard(c, nil).Status)
+	assert.Equal(t, "before", c.text, "the previous content is restored")
+
+	assert.Equal(t, Fail, CheckClipboard(&fakeClipboard{copyErr: errors.New("xclip failed")}, nil).Status)
+	assert.Equal(t, Fail, CheckClipboard(nil, errors.New("clipboard provider xclip is not installed")).Status)
+
+	osc52 := &fakeClipboard{text: "kept", pasteErr: clipboard.ErrCannotPaste}
+	assert.Equal(t, Warn, CheckClipboard(osc52, nil).Status)
+	assert.Equal(t, "kept", osc52.text, "the clipboard is not overwritten")
+
+	unreadable := &fakeClipboard{text: "kept", pasteErr: errors.New("xclip: no display")}
+	check := CheckClipboard(unreadable, nil)
+	assert.Equal(t, Warn, check.Status)
+	assert.Contains(t, check.Detail, "no display")
+	assert.Equal(t, "kept", unreadable.text, "the clipboard is not overwritten")
+}
+
+func TestCheckProject(t *testing.T) {
+	newClient := func(protection string) func(config.Project) (client.Client, error) {
+		return func(project config.Project) (client.Client, error) {
+			return client.New(project.ID, "fake", client.Options{Protection: protection})
+		}
+	}
+	project := config.Project{ID: "demo-project", Type: "fake"}
+
+	section := CheckProject(project, newClient(client.ProtectionNormal))
+	assert.Len(t, section.Checks, 3)
+	for _, check := range section.Checks {
+		assert.Equal(t, Pass, check.Status, check.Name)
+	}
+	assert.Equal(t, "as developer@example.com", section.Checks[0].Detail)
+
+	readOnly := config.Project{ID: "demo-project", Type: "fake", Protection: client.ProtectionReadOnly}
+	section = CheckProject(readOnly, newClient(client.ProtectionReadOnly))
+	assert.Equal(t, Pass, section.Checks[2].Status)
+	assert.Contains(t, section.Checks[2].Detail, "read-only")
+
+	filtered := func(project config.Project) (client.Client, error) {
+		return client.New(project.ID, "fake", client.Options{Filter: "labels.environment:prod"})
+	}
+	section = CheckProject(project, filtered)
+	assert.Equal(t, Pass, section.Checks[1].Status)
+	assert.Regexp(t, `^\d+ secrets listed, 0 match the saved filter labels.environment:prod$`, section.Checks[1].Detail)
+
+	failing := func(config.Project) (client.Client, error) {
+		return nil, errors.New("google: could not find default credentials")
+	}
+	section = CheckProject(project, failing)
+	assert.Equal(t, Fail, section.Checks[0].Status)
+	assert.Contains(t, section.Checks[0].Hint, "application-default login")
+}
+
+func TestRunAndPrint(t *testing.T) {
+	env := Environment{
+		ConfigFile: filepath.Join(t.TempDir(), "config.yaml"),
+		Editor:     "vim",
+		LookPath:   func(file string) (string, error) { return "", errors.New("not found") },
+		Clipboard:  &fakeClipboard{},
+		Projects:   []config.Project{{ID: "demo-project", Type: "fake"}},
+		NewClient: func(project config.Project) (client.Client, error) {
+			return client.New(project.ID, project.Type, client.Options{})
+		},
+	}
+
+	report := Run(env)
+	assert.True(t, report.Failed())
+	assert.Len(t, report.Sections, 2)
+
+	var out bytes.Buffer
+	Print(&out, report)
+	assert.Contains(t, out.String(), "✖ editor")
+	assert.Contains(t, out.String(), "→ install vim")
+	assert.Contains(t, out.String(), "Project demo-project")
+}
@@ -1,11 +1,14 @@
 package editor
 
 import (
+	"bytes"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"smm/internal/client"
 	"smm/internal/view"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -16,17 +19,36 @@ type EditFinishedMsg struct {
 	SecretData    []byte
 }
 
-func OpenEditor(secretData string, currentSecret view.Secret) tea.Cmd {
+// SettingsEditedMsg carries the settings document of CurrentSecret once the
+// editor is closed.
+type SettingsEditedMsg struct {
+	Equal         bool
+	CurrentSecret view.Secret
+	Document      []byte
+}
+
+// NewSecretEditedMsg carries the first version of a secret about to be
+// created once the editor is closed.
+type NewSecretEditedMsg struct {
+	SecretName string
+	Settings   client.SecretSettings
+	SecretData []byte
+}
+
+func editorCommand(filePath string) *exec.Cmd {
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = "vim"
 	}
+	return exec.Command(editor, filePath)
+}
 
+func OpenEditor(secretData string, currentSecret view.Secret) tea.Cmd {
 	tempDir := os.TempDir()
 	hash := currentSecret.Hash()
 	filePath := filepath.Join(tempDir, hash)
 
-	c := exec.Command(editor, filePath)
+	c := editorCommand(filePath)
 	return tea.ExecProcess(c, func(err error) tea.Msg {
 		fileContent, err := os.ReadFile(filePath)
 		equal := string(fileContent) == secretData
@@ -37,3 +59,35 @@ func OpenEditor(secretData string, currentSecret view.Secret) tea.Cmd {
 		return EditFinishedMsg{equal, currentSecret, fileContent}
 	})
 }
+
+// EditSettings opens document in the editor from a private temporary file.
+func EditSettings(document []byte, currentSecret view.Secret) (tea.Cmd, error) {
+	filePath := filepath.Join(os.TempDir(), strings.TrimSuffix(currentSecret.Hash(), ".env")+".settings.yaml")
+	if err := os.WriteFile(filePath, document, 0600); err != nil {
+		return nil, err
+	}
+
+	return tea.ExecProcess(editorCommand(filePath), func(err error) tea.Msg {
+		fileContent, _ := os.ReadFile(filePath)
+		_ = os.Remove(filePath)
+
+		return SettingsEditedMsg{bytes.Equal(fileContent, document), currentSecret, fileContent}
+	}), nil
+}
+
+// EditNewSecret opens an empty private temporary file for the first version
+// of secretName.
+func EditNewSecret(secretName string, settings client.SecretSettings) (tea.Cmd, error) {
+	secret := view.NewSecret(secretName, "", "current", 0, time.Time{})
+	filePath := filepath.Join(os.TempDir(), secret.Hash())
+	if err := os.WriteFile(filePath, nil, 0600); err != nil {
+		return nil, err
+	}
+
+	return tea.ExecProcess(editorCommand(filePath), func(err error) tea.Msg {
+		fileContent, _ := os.ReadFile(filePath)
+		_ = os.Remove(filePath)
+
+		return NewSecretEditedMsg{secretName, settings, []byte(strings.TrimRight(string(fileContent), "\n\r"))}
+	}), nil
+}
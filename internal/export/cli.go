@@ -0,0 +1,146 @@
+package export
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"smm/internal/bootstrap"
+	"smm/internal/client"
+	"smm/internal/config"
+	"strings"
+)
+
+// Fetch reads the payload of the secret at fullPath, its latest version when
+// version is empty.
+func Fetch(c client.Client, name, fullPath, version string) (Secret, error) {
+	var data []byte
+	var err error
+	if version == "" {
+		data, err = c.GetSecret(fullPath)
+	} else {
+		data, err = c.GetSecretVersion(fullPath, version)
+	}
+	if err != nil {
+		return Secret{}, err
+	}
+	return Secret{Name: name, Data: data}, nil
+}
+
+type recipientsFlag []string
+
+func (r *recipientsFlag) String() string {
+	return strings.Join(*r, ",")
+}
+
+func (r *recipientsFlag) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
+
+// Main runs `smm export` with the arguments following it, returning the
+// exit code. projectId, given to smm before the command, is the default of
+// its -p.
+func Main(args []string, projectId string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&projectId, "p", projectId, "Project ID to export from")
+	formatName := flags.String("f", string(Dotenv), "Format: dotenv, json, yaml, k8s, docker or systemd")
+	output := flags.String("o", "", "File to write, created with 0600 permissions; stdout when empty")
+	force := flags.Bool("force", false, "Overwrite the file given with -o if it exists")
+	name := flags.String("name", "", "Name of the Kubernetes Secret")
+	var recipients recipientsFlag
+	flags.Var(&recipients, "r", "age recipient to encrypt to, repeatable")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: smm export [flags] secret[@version]...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	format, err := ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	_ = config.Load()
+	projectId = config.ResolveProjectId(projectId)
+	if projectId == "" {
+		fmt.Fprintln(stderr, "no project given, use -p or configure a default project")
+		return 1
+	}
+
+	c, err := client.New(projectId, config.GetTypeByProjectId(projectId), bootstrap.ClientOptions(projectId))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer c.Close()
+
+	secrets, err := fetchArgs(c, flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	content, err := Render(format, secrets, *name)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if *output == "" {
+		if len(recipients) > 0 {
+			if content, err = Encrypt(content, recipients); err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+		}
+		_, _ = stdout.Write(content)
+		return 0
+	}
+	if err := WriteFile(*output, content, recipients, *force); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "Exported %d secrets to %s\n", len(secrets), *output)
+	return 0
+}
+
+// fetchArgs fetches the secrets named in args, each optionally pinned to a
+// version as in db-creds@3. Names are looked up past the saved filter, which
+// only narrows the list in the UI.
+func fetchArgs(c client.Client, args []string) ([]Secret, error) {
+	infos, err := c.AllSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []Secret
+	for _, arg := range args {
+		name, version, _ := strings.Cut(arg, "@")
+		fullPath := ""
+		for _, info := range infos {
+			if info.Name == name || info.FullPath == name {
+				fullPath = info.FullPath
+				name = info.Name
+				break
+			}
+		}
+		if fullPath == "" {
+			return nil, fmt.Errorf("secret %q not found", name)
+		}
+
+		secret, err := Fetch(c, name, fullPath, version)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
@@ -0,0 +1,246 @@
+// Package export converts env and JSON secrets into the files local stacks
+// read their configuration from: dotenv, JSON, YAML, a Kubernetes Secret, a
+// docker --env-file or a systemd EnvironmentFile.
+package export
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"smm/internal/payload"
+	"smm/internal/ui"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	Dotenv     Format = "dotenv"
+	JSON       Format = "json"
+	YAML       Format = "yaml"
+	Kubernetes Format = "k8s"
+	Docker     Format = "docker"
+	Systemd    Format = "systemd"
+)
+
+// Formats lists every format, in the order the export form cycles through.
+var Formats = []Format{Dotenv, JSON, YAML, Kubernetes, Docker, Systemd}
+
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		names[i] = string(format)
+	}
+	return "", fmt.Errorf("unknown export format %q, use %s", name, strings.Join(names, ", "))
+}
+
+// Extension is the file extension usually given to the format.
+func (f Format) Extension() string {
+	switch f {
+	case JSON:
+		return ".json"
+	case YAML, Kubernetes:
+		return ".yaml"
+	}
+	return ".env"
+}
+
+// Secret is the payload of a secret to export, with its short name.
+type Secret struct {
+	Name string
+	Data []byte
+}
+
+type Variable struct {
+	Name  string
+	Value string
+}
+
+// Variables flattens the secrets into variables. Env payloads keep their
+// names, JSON leaves are named after their path, so db.host becomes DB_HOST,
+// and any other payload becomes a single variable named after the secret. A
+// variable set by a later secret replaces the earlier one.
+func Variables(secrets []Secret) []Variable {
+	var variables []Variable
+	set := func(name, value string) {
+		i := slices.IndexFunc(variables, func(v Variable) bool { return v.Name == name })
+		if i >= 0 {
+			variables[i].Value = value
+			return
+		}
+		variables = append(variables, Variable{Name: name, Value: value})
+	}
+
+	for _, secret := range secrets {
+		format, entries := payload.FormatRaw, []payload.Entry(nil)
+		switch ui.DetectFormat(secret.Data) {
+		case "bash", "json":
+			format, entries = payload.Parse(secret.Data)
+		}
+
+		switch format {
+		case payload.FormatEnv:
+			for _, entry := range entries {
+				set(entry.Key, entry.Value)
+			}
+		case payload.FormatJSON:
+			for _, entry := range entries {
+				set(payload.EnvName(entry.Key), entry.Value)
+			}
+		default:
+			set(payload.EnvName(secret.Name), string(secret.Data))
+		}
+	}
+	return variables
+}
+
+// Render writes the secrets in format. name is the name of the Kubernetes
+// Secret, derived from the first secret when empty.
+func Render(format Format, secrets []Secret, name string) ([]byte, error) {
+	variables := Variables(secrets)
+	if format != Kubernetes {
+		for _, v := range variables {
+			if !utf8.ValidString(v.Value) {
+				return nil, fmt.Errorf("%s holds binary data, only the k8s format can export it", v.Name)
+			}
+		}
+	}
+
+	switch format {
+	case Dotenv:
+		return renderLines(variables, dotenvQuote)
+	case Docker:
+		return renderLines(variables, dockerValue)
+	case Systemd:
+		return renderLines(variables, systemdQuote)
+	case JSON:
+		out, err := json.MarshalIndent(valueMap(variables), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(out, '\n'), nil
+	case YAML:
+		return marshalYAML(valueMap(variables))
+	case Kubernetes:
+		if name == "" && len(secrets) > 0 {
+			name = ResourceName(secrets[0].Name)
+		}
+		return marshalYAML(newManifest(name, variables))
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+func renderLines(variables []Variable, value func(string) (string, error)) ([]byte, error) {
+	var out bytes.Buffer
+	for _, v := range variables {
+		rendered, err := value(v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v.Name, err)
+		}
+		fmt.Fprintf(&out, "%s=%s\n", v.Name, rendered)
+	}
+	return out.Bytes(), nil
+}
+
+var dotenvBare = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,-]*$`)
+
+// dotenvQuote leaves simple values bare and double-quotes the rest, escaping
+// what dotenv loaders and shells would otherwise expand.
+func dotenvQuote(value string) (string, error) {
+	if dotenvBare.MatchString(value) {
+		return value, nil
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`", "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`, nil
+}
+
+// dockerValue writes the value as is: docker takes everything after the
+// first = literally and cannot continue a value on the next line.
+func dockerValue(value string) (string, error) {
+	if strings.ContainsAny(value, "\r\n") {
+		return "", fmt.Errorf("docker env files cannot hold multi-line values")
+	}
+	return value, nil
+}
+
+// systemdQuote double-quotes the value, which systemd unquotes keeping
+// spaces. Its line continuations drop the newline, so those are rejected.
+func systemdQuote(value string) (string, error) {
+	if strings.ContainsAny(value, "\r\n") {
+		return "", fmt.Errorf("systemd environment files cannot hold multi-line values")
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`, nil
+}
+
+func valueMap(variables []Variable) map[string]string {
+	values := make(map[string]string, len(variables))
+	for _, v := range variables {
+		values[v.Name] = v.Value
+	}
+	return values
+}
+
+func marshalYAML(value any) ([]byte, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+type manifest struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   metadata          `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	Data       map[string]string `yaml:"data"`
+}
+
+type metadata struct {
+	Name string `yaml:"name"`
+}
+
+func newManifest(name string, variables []Variable) manifest {
+	data := make(map[string]string, len(variables))
+	for _, v := range variables {
+		data[v.Name] = base64.StdEncoding.EncodeToString([]byte(v.Value))
+	}
+	return manifest{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   metadata{Name: name},
+		Type:       "Opaque",
+		Data:       data,
+	}
+}
+
+var resourceNameInvalid = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// ResourceName turns a secret name into a valid Kubernetes name, e.g.
+// "DB_Creds" into db-creds.
+func ResourceName(name string) string {
+	name = strings.Trim(resourceNameInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if len(name) > 253 {
+		name = strings.Trim(name[:253], "-.")
+	}
+	if name == "" {
+		return "secret"
+	}
+	return name
+}
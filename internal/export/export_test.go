@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/stretchr/testify/assert"
+	"smm/internal/client"
+)
+
+var testSecrets = []Secret{
+	{Name: "app-env", Data: []byte("API_KEY=abc123\nGREETING=hello world\n")},
+	{Name: "db-creds", Data: []byte(`{"db": {"host": "localhost", "password": "p\"ss"}}`)},
+	{Name: "tls-key", Data: []byte("-----BEGIN KEY-----")},
+}
+
+func TestVariables(t *testing.T) {
+	variables := Variables(append(testSecrets, Secret{Name: "override", Data: []byte("API_KEY=xyz")}))
+
+	assert.Equal(t, []Variable{
+		{"API_KEY", "xyz"},
+		{"GREETING", "hello world"},
+		{"DB_HOST", "localhost"},
+		{"DB_PASSWORD", `p"ss`},
+		{"TLS_KEY", "-----BEGIN KEY-----"},
+	}, variables)
+}
+
+func TestRenderLines(t *testing.T) {
+	out, err := Render(Dotenv, testSecrets, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "API_KEY=abc123\nGREETING=\"hello world\"\nDB_HOST=localhost\nDB_PASSWORD=\"p\\\"ss\"\nTLS_KEY=\"-----BEGIN KEY-----\"\n", string(out))
+
+	out, err = Render(Docker, testSecrets, "")
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "GREETING=hello world\n")
+
+	out, err = Render(Systemd, testSecrets, "")
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "DB_PASSWORD=\"p\\\"ss\"\n")
+
+	multiline := []Secret{{Name: "cert", Data: []byte("line one\nline two")}}
+	_, err = Render(Docker, multiline, "")
+	assert.ErrorContains(t, err, "multi-line")
+	out, err = Render(Dotenv, multiline, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "CERT=\"line one\\nline two\"\n", string(out))
+}
+
+func TestRenderStructured(t *testing.T) {
+	out, err := Render(JSON, testSecrets[:1], "")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"API_KEY": "abc123", "GREETING": "hello world"}`, string(out))
+
+	out, err = Render(YAML, testSecrets[:1], "")
+	assert.NoError(t, err)
+	assert.Equal(t, "API_KEY: abc123\nGREETING: hello world\n", string(out))
+}
+
+func TestRenderKubernetes(t *testing.T) {
+	out, err := Render(Kubernetes, []Secret{{Name: "App_Env", Data: []byte("API_KEY=abc123")}}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "apiVersion: v1\nkind: Secret\nmetadata:\n  name: app-env\ntype: Opaque\ndata:\n  API_KEY: YWJjMTIz\n", string(out))
+
+	out, err = Render(Kubernetes, []Secret{{Name: "blob", Data: []byte{0xff, 0xfe}}}, "custom")
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "name: custom\n")
+	assert.Contains(t, string(out), "BLOB: //4=\n")
+
+	_, err = Render(Dotenv, []Secret{{Name: "blob", Data: []byte{0xff, 0xfe}}}, "")
+	assert.ErrorContains(t, err, "binary data")
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("k8s")
+	assert.NoError(t, err)
+	assert.Equal(t, Kubernetes, format)
+
+	_, err = ParseFormat("toml")
+	assert.ErrorContains(t, err, "unknown export format")
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	assert.NoError(t, os.WriteFile(path, []byte("old contents that are longer"), 0644))
+
+	err := WriteFile(path, []byte("A=1\n"), nil, false)
+	assert.ErrorIs(t, err, fs.ErrExist)
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "old contents that are longer", string(data))
+
+	assert.NoError(t, WriteFile(path, []byte("A=1\n"), nil, true))
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	data, _ = os.ReadFile(path)
+	assert.Equal(t, "A=1\n", string(data))
+}
+
+func TestWriteFileEncrypted(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), ".env.age")
+
+	assert.NoError(t, WriteFile(path, []byte("A=1\n"), []string{identity.Recipient().String()}, false))
+
+	data, _ := os.ReadFile(path)
+	assert.True(t, bytes.HasPrefix(data, []byte(armor.Header)))
+	r, err := age.Decrypt(armor.NewReader(bytes.NewReader(data)), identity)
+	assert.NoError(t, err)
+	plain, _ := io.ReadAll(r)
+	assert.Equal(t, "A=1\n", string(plain))
+
+	assert.ErrorContains(t, WriteFile(path, []byte("A=1\n"), []string{"not-a-key"}, true), "invalid age recipient")
+}
+
+func TestFetchArgsIgnoresFilter(t *testing.T) {
+	fake, _ := client.NewFakeClient("test-project")
+	_, _ = fake.CreateSecret("db-url", client.SecretSettings{}, []byte("postgres://live"))
+	_ = fake.SetFilter("name:api-key")
+
+	secrets, err := fetchArgs(fake, []string{"db-url@1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Secret{{Name: "db-url", Data: []byte("postgres://live")}}, secrets)
+
+	_, err = fetchArgs(fake, []string{"missing"})
+	assert.ErrorContains(t, err, `secret "missing" not found`)
+}
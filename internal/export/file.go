@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// WriteFile writes content to path readable by its owner only, encrypted
+// with age to recipients when there are any. An existing file is only
+// replaced with overwrite, which truncates it and tightens its permissions;
+// otherwise the error matches fs.ErrExist.
+func WriteFile(path string, content []byte, recipients []string, overwrite bool) error {
+	if len(recipients) > 0 {
+		encrypted, err := Encrypt(content, recipients)
+		if err != nil {
+			return err
+		}
+		content = encrypted
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0600)
+	if err != nil {
+		return err
+	}
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Encrypt encrypts content to age X25519 recipients, such as age1ql3z7...,
+// in the ASCII armored format.
+func Encrypt(content []byte, recipients []string) ([]byte, error) {
+	var parsed []age.Recipient
+	for _, recipient := range recipients {
+		r, err := age.ParseX25519Recipient(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient %q: %w", recipient, err)
+		}
+		parsed = append(parsed, r)
+	}
+
+	var out bytes.Buffer
+	armored := armor.NewWriter(&out)
+	w, err := age.Encrypt(armored, parsed...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(content); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := armored.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"smm/internal/client"
+)
+
+type Action int
+
+const (
+	// Create makes a new secret.
+	Create Action = iota
+	// Update adds a version to an existing secret.
+	Update
+	// Skip leaves a secret whose latest version already holds the payload.
+	Skip
+	// Invalid cannot be imported, as told by the step's Err.
+	Invalid
+)
+
+// Step is what importing an item does to the project.
+type Step struct {
+	Item
+	Action Action
+	// Existing is the secret getting a new version or skipped.
+	Existing client.SecretInfo
+	Err      error
+}
+
+// Plan decides for each item whether to create its secret, add a version or
+// skip it because its latest version holds the same payload. Secrets outside
+// the filter of c count as existing too.
+func Plan(c client.Client, items []Item) ([]Step, error) {
+	secretInfos, err := c.AllSecrets()
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]client.SecretInfo{}
+	for _, secretInfo := range secretInfos {
+		if secretInfo.Location == "" {
+			existing[secretInfo.Name] = secretInfo
+		}
+	}
+
+	steps := make([]Step, len(items))
+	seen := map[string]bool{}
+	for i, item := range items {
+		step := Step{Item: item}
+		secretInfo, found := existing[item.Name]
+		nameErr := client.ValidateSecretName(item.Name)
+		switch {
+		case nameErr != nil:
+			step.Action = Invalid
+			step.Err = nameErr
+		case seen[item.Name]:
+			step.Action = Invalid
+			step.Err = fmt.Errorf("%s is imported twice", item.Name)
+		case len(item.Payload) == 0:
+			step.Action = Invalid
+			step.Err = fmt.Errorf("empty value")
+		case !found:
+			step.Action = Create
+		default:
+			step.Existing = secretInfo
+			step.Action = Update
+			// A latest version that cannot be read is replaced.
+			if current, err := c.GetSecret(secretInfo.FullPath); err == nil && bytes.Equal(current, item.Payload) {
+				step.Action = Skip
+			}
+		}
+		seen[item.Name] = true
+		steps[i] = step
+	}
+	return steps, nil
+}
+
+// Apply carries out step, adding labels to the secret. Skipped secrets only
+// get the labels they are missing. A created secret that cannot be labelled
+// is deleted again, so that the next import creates it.
+func Apply(c client.Client, step Step, labels map[string]string) error {
+	switch step.Action {
+	case Invalid:
+		return step.Err
+	case Create:
+		created, err := c.CreateSecret(step.Name, client.SecretSettings{}, step.Payload)
+		if err != nil {
+			return err
+		}
+		if len(labels) == 0 {
+			return nil
+		}
+		if err := c.SetLabels(created.FullPath, labels); err != nil {
+			if deleteErr := c.DeleteSecret(created.FullPath); deleteErr != nil {
+				return fmt.Errorf("%w; %s was created without its labels and could not be deleted: %v", err, step.Name, deleteErr)
+			}
+			return err
+		}
+		return nil
+	case Update:
+		if err := c.AddSecretVersion(step.Existing.FullPath, step.Payload); err != nil {
+			return err
+		}
+	}
+
+	merged := maps.Clone(step.Existing.Labels)
+	if merged == nil {
+		merged = map[string]string{}
+	}
+	maps.Copy(merged, labels)
+	if maps.Equal(merged, step.Existing.Labels) || len(labels) == 0 {
+		return nil
+	}
+	return c.SetLabels(step.Existing.FullPath, merged)
+}
+
+// Summary counts the steps by action, e.g. "3 created, 2 updated, 1
+// unchanged".
+func Summary(steps []Step) string {
+	counts := map[Action]int{}
+	for _, step := range steps {
+		counts[step.Action]++
+	}
+	summary := fmt.Sprintf("%d created, %d updated, %d unchanged", counts[Create], counts[Update], counts[Skip])
+	if counts[Invalid] > 0 {
+		summary += fmt.Sprintf(", %d invalid", counts[Invalid])
+	}
+	return summary
+}
@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"smm/internal/client"
+)
+
+func TestPlan(t *testing.T) {
+	fake, _ := client.NewFakeClient("test-project")
+	existing, _ := fake.CreateSecret("api-key", client.SecretSettings{}, []byte("abc123"))
+	_, _ = fake.CreateSecret("db-url", client.SecretSettings{}, []byte("postgres://old"))
+
+	steps, err := Plan(fake, []Item{
+		{Name: "api-key", Payload: []byte("abc123")},
+		{Name: "db-url", Payload: []byte("postgres://new")},
+		{Name: "new-token", Payload: []byte("t0k3n")},
+		{Name: "bad.name", Payload: []byte("x")},
+		{Name: "new-token", Payload: []byte("again")},
+		{Name: "empty", Payload: nil},
+	})
+
+	assert.NoError(t, err)
+	actions := make([]Action, len(steps))
+	for i, step := range steps {
+		actions[i] = step.Action
+	}
+	assert.Equal(t, []Action{Skip, Update, Create, Invalid, Invalid, Invalid}, actions)
+	assert.Equal(t, existing.FullPath, steps[0].Existing.FullPath)
+	assert.ErrorContains(t, steps[4].Err, "imported twice")
+	assert.Equal(t, "1 created, 1 updated, 1 unchanged, 3 invalid", Summary(steps))
+}
+
+func TestApply(t *testing.T) {
+	fake, _ := client.NewFakeClient("test-project")
+	_, _ = fake.CreateSecret("db-url", client.SecretSettings{}, []byte("postgres://old"))
+	labels := map[string]string{"team": "payments"}
+
+	steps, _ := Plan(fake, []Item{
+		{Name: "db-url", Payload: []byte("postgres://new")},
+		{Name: "new-token", Payload: []byte("t0k3n")},
+		{Name: "bad.name", Payload: []byte("x")},
+	})
+
+	assert.NoError(t, Apply(fake, steps[0], labels))
+	assert.NoError(t, Apply(fake, steps[1], labels))
+	assert.Error(t, Apply(fake, steps[2], labels))
+
+	for _, fullPath := range []string{steps[0].Existing.FullPath, "projects/test-project/secrets/new-token"} {
+		secretInfo, _ := fake.GetSecretInfo(fullPath)
+		assert.Equal(t, labels, secretInfo.Labels)
+	}
+	data, _ := fake.GetSecret(steps[0].Existing.FullPath)
+	assert.Equal(t, "postgres://new", string(data))
+
+	steps, _ = Plan(fake, []Item{{Name: "db-url", Payload: []byte("postgres://new")}})
+	assert.Equal(t, Skip, steps[0].Action)
+}
+
+func TestPlanIgnoresFilter(t *testing.T) {
+	fake, _ := client.NewFakeClient("test-project")
+	existing, _ := fake.CreateSecret("db-url", client.SecretSettings{}, []byte("postgres://old"))
+	_ = fake.SetFilter("name:api-key")
+
+	steps, err := Plan(fake, []Item{{Name: "db-url", Payload: []byte("postgres://new")}})
+	assert.NoError(t, err)
+	assert.Equal(t, Update, steps[0].Action)
+	assert.Equal(t, existing.FullPath, steps[0].Existing.FullPath)
+}
+
+// failingLabels is a client whose SetLabels always fails.
+type failingLabels struct {
+	client.Client
+}
+
+func (failingLabels) SetLabels(string, map[string]string) error {
+	return errors.New("labels rejected")
+}
+
+func TestApplyDeletesUnlabelledSecret(t *testing.T) {
+	fake, _ := client.NewFakeClient("test-project")
+	steps, _ := Plan(fake, []Item{{Name: "new-token", Payload: []byte("t0k3n")}})
+
+	err := Apply(failingLabels{fake}, steps[0], map[string]string{"team": "payments"})
+	assert.ErrorContains(t, err, "labels rejected")
+
+	steps, _ = Plan(fake, []Item{{Name: "new-token", Payload: []byte("t0k3n")}})
+	assert.Equal(t, Create, steps[0].Action)
+}
@@ -0,0 +1,112 @@
+// Package importer creates secrets in bulk from a dotenv, JSON or YAML file,
+// where each top-level key becomes a secret, or from a directory, where each
+// file becomes a secret.
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"smm/internal/payload"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Item is a secret to import with the payload of its new version.
+type Item struct {
+	Name    string
+	Payload []byte
+}
+
+// Read returns the items of the file or directory at path, in the order
+// they appear.
+func Read(path string) ([]Item, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return readDir(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml":
+		return readMapping(data)
+	}
+	return readDotenv(data)
+}
+
+// readDir names a secret after each file of dir, without its extension.
+// Hidden files and subdirectories are skipped.
+func readDir(dir string) ([]Item, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Item
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		items = append(items, Item{Name: name, Payload: data})
+	}
+	return items, nil
+}
+
+func readDotenv(data []byte) ([]Item, error) {
+	format, entries := payload.Parse(data)
+	if format != payload.FormatEnv {
+		return nil, fmt.Errorf("not a dotenv, JSON or YAML file")
+	}
+
+	items := make([]Item, len(entries))
+	for i, entry := range entries {
+		items[i] = Item{Name: entry.Key, Payload: []byte(entry.Value)}
+	}
+	return items, nil
+}
+
+// readMapping reads a JSON or YAML object, which YAML covers both of. Scalar
+// values are stored as they are written and nested ones as indented JSON.
+func readMapping(data []byte) ([]Item, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the file must hold an object whose keys are the secret names")
+	}
+
+	mapping := document.Content[0]
+	var items []Item
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if value.Kind == yaml.ScalarNode {
+			items = append(items, Item{Name: key.Value, Payload: []byte(value.Value)})
+			continue
+		}
+
+		var decoded any
+		if err := value.Decode(&decoded); err != nil {
+			return nil, fmt.Errorf("%s: %w", key.Value, err)
+		}
+		encoded, err := json.MarshalIndent(decoded, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key.Value, err)
+		}
+		items = append(items, Item{Name: key.Value, Payload: encoded})
+	}
+	return items, nil
+}
@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestReadDotenv(t *testing.T) {
+	path := writeFile(t, t.TempDir(), ".env", "# api\nAPI_KEY=abc123\nexport DB_URL=\"postgres://db\"\n")
+
+	items, err := Read(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Item{
+		{Name: "API_KEY", Payload: []byte("abc123")},
+		{Name: "DB_URL", Payload: []byte("postgres://db")},
+	}, items)
+}
+
+func TestReadJSON(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "secrets.json", `{"api-key": "abc123", "db": {"host": "localhost"}, "port": 5432}`)
+
+	items, err := Read(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Item{
+		{Name: "api-key", Payload: []byte("abc123")},
+		{Name: "db", Payload: []byte("{\n  \"host\": \"localhost\"\n}")},
+		{Name: "port", Payload: []byte("5432")},
+	}, items)
+}
+
+func TestReadYAML(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "secrets.yaml", "api-key: abc123\ncert: |\n  line one\n  line two\n")
+
+	items, err := Read(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Item{
+		{Name: "api-key", Payload: []byte("abc123")},
+		{Name: "cert", Payload: []byte("line one\nline two\n")},
+	}, items)
+
+	_, err = Read(writeFile(t, t.TempDir(), "list.yaml", "- a\n- b\n"))
+	assert.ErrorContains(t, err, "must hold an object")
+}
+
+func TestReadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "api-key.txt", "abc123")
+	writeFile(t, dir, "tls-cert", "-----BEGIN CERTIFICATE-----")
+	writeFile(t, dir, ".DS_Store", "skipped")
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0700))
+
+	items, err := Read(dir)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Item{
+		{Name: "api-key", Payload: []byte("abc123")},
+		{Name: "tls-cert", Payload: []byte("-----BEGIN CERTIFICATE-----")},
+	}, items)
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"fmt"
+	"os/exec"
+	"smm/internal/config"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+// idleTickMsg checks whether smm has been idle for long enough to lock.
+// Ticks of an older generation are ignored, so that a single timer runs.
+type idleTickMsg struct {
+	generation int
+}
+
+// unlockResultMsg is the outcome of the unlock command.
+type unlockResultMsg struct {
+	err error
+}
+
+// lock hides secrets after a period without keypresses or when the terminal
+// loses focus, until a keypress or the unlock command resumes.
+type lock struct {
+	locked        bool
+	reason        string
+	alert         string
+	after         time.Duration
+	onBlur        bool
+	unlockCommand []string
+	lastActivity  time.Time
+	generation    int
+}
+
+func newLock() lock {
+	return lock{
+		after:         config.GetLockAfter(),
+		onBlur:        config.GetLockOnBlur(),
+		unlockCommand: config.GetUnlockCommand(),
+		lastActivity:  time.Now(),
+	}
+}
+
+// tick starts a new idle timer firing when smm would become idle.
+func (l *lock) tick() tea.Cmd {
+	if l.after <= 0 {
+		return nil
+	}
+	l.generation++
+	generation := l.generation
+	return tea.Tick(l.after-time.Since(l.lastActivity), func(time.Time) tea.Msg {
+		return idleTickMsg{generation: generation}
+	})
+}
+
+func (m *Model) lockScreen(reason string) {
+	if m.lock.locked {
+		return
+	}
+	m.lock.locked = true
+	m.lock.reason = reason
+	m.lock.alert = ""
+	if m.page != nil {
+		m.page.Lock()
+	}
+}
+
+// updateLock handles the messages of the lock, reporting whether msg was
+// consumed.
+func (m *Model) updateLock(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case tea.BlurMsg:
+		if m.lock.onBlur {
+			m.lockScreen("The terminal lost focus")
+		}
+		return nil, true
+	case tea.FocusMsg:
+		return nil, true
+	case idleTickMsg:
+		if msg.generation != m.lock.generation || m.lock.locked {
+			return nil, true
+		}
+		if time.Since(m.lock.lastActivity) >= m.lock.after {
+			m.lockScreen(fmt.Sprintf("Idle for %s", m.lock.after))
+			return nil, true
+		}
+		return m.lock.tick(), true
+	case unlockResultMsg:
+		if msg.err != nil {
+			log.Warn().Err(msg.err).Msg("Unlock command failed")
+			m.lock.alert = "Re-authentication failed, press any key to retry"
+			return nil, true
+		}
+		return m.unlock(), true
+	case tea.KeyMsg:
+		if !m.lock.locked {
+			m.lock.lastActivity = time.Now()
+			return nil, false
+		}
+		if msg.String() == "ctrl+c" {
+			if m.page != nil {
+				m.page.Quit()
+			}
+			return tea.Quit, true
+		}
+		if len(m.lock.unlockCommand) == 0 {
+			return m.unlock(), true
+		}
+		command := exec.Command(m.lock.unlockCommand[0], m.lock.unlockCommand[1:]...)
+		return tea.ExecProcess(command, func(err error) tea.Msg {
+			return unlockResultMsg{err: err}
+		}), true
+	case tea.MouseMsg:
+		return nil, m.lock.locked
+	}
+	return nil, false
+}
+
+func (m *Model) unlock() tea.Cmd {
+	m.lock.locked = false
+	m.lock.alert = ""
+	m.lock.lastActivity = time.Now()
+	return m.lock.tick()
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePage counts the times it was locked and quit.
+type fakePage struct {
+	locks int
+	quits int
+}
+
+func (p *fakePage) Init()                  {}
+func (p *fakePage) View() string           { return "" }
+func (p *fakePage) Resize(int, int)        {}
+func (p *fakePage) Update(tea.Msg) tea.Cmd { return nil }
+func (p *fakePage) SelectSecret(string)    {}
+func (p *fakePage) Lock()                  { p.locks++ }
+func (p *fakePage) Close()                 {}
+func (p *fakePage) Quit()                  { p.quits++ }
+
+func newTestModel(l lock) (*Model, *fakePage) {
+	page := &fakePage{}
+	return &Model{page: page, lock: l}, page
+}
+
+func TestIdleTimeoutLocks(t *testing.T) {
+	m, page := newTestModel(lock{after: time.Minute, lastActivity: time.Now(), generation: 1})
+
+	cmd, consumed := m.updateLock(idleTickMsg{generation: 1})
+	assert.True(t, consumed)
+	assert.NotNil(t, cmd, "a tick before the timeout starts another timer")
+	assert.False(t, m.lock.locked)
+	assert.Equal(t, 2, m.lock.generation)
+
+	m.lock.lastActivity = time.Now().Add(-2 * time.Minute)
+	_, consumed = m.updateLock(idleTickMsg{generation: 2})
+	assert.True(t, consumed)
+	assert.True(t, m.lock.locked)
+	assert.Equal(t, "Idle for 1m0s", m.lock.reason)
+	assert.Equal(t, 1, page.locks)
+}
+
+func TestStaleIdleTickIsIgnored(t *testing.T) {
+	m, page := newTestModel(lock{after: time.Minute, lastActivity: time.Now().Add(-2 * time.Minute), generation: 2})
+
+	cmd, consumed := m.updateLock(idleTickMsg{generation: 1})
+	assert.True(t, consumed)
+	assert.Nil(t, cmd)
+	assert.False(t, m.lock.locked)
+	assert.Equal(t, 2, m.lock.generation)
+	assert.Zero(t, page.locks)
+}
+
+func TestBlurLocks(t *testing.T) {
+	m, page := newTestModel(lock{onBlur: false})
+	_, consumed := m.updateLock(tea.BlurMsg{})
+	assert.True(t, consumed)
+	assert.False(t, m.lock.locked)
+
+	m, page = newTestModel(lock{onBlur: true})
+	_, consumed = m.updateLock(tea.BlurMsg{})
+	assert.True(t, consumed)
+	assert.True(t, m.lock.locked)
+	assert.Equal(t, "The terminal lost focus", m.lock.reason)
+	assert.Equal(t, 1, page.locks)
+}
+
+func TestFailedUnlockKeepsLocked(t *testing.T) {
+	m, _ := newTestModel(lock{after: time.Minute, locked: true, unlockCommand: []string{"sudo", "-k", "true"}})
+
+	cmd, consumed := m.updateLock(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, consumed)
+	assert.NotNil(t, cmd, "a keypress runs the unlock command")
+	assert.True(t, m.lock.locked)
+
+	_, consumed = m.updateLock(unlockResultMsg{err: errors.New("incorrect password")})
+	assert.True(t, consumed)
+	assert.True(t, m.lock.locked)
+	assert.Contains(t, m.lock.alert, "Re-authentication failed")
+
+	_, consumed = m.updateLock(unlockResultMsg{})
+	assert.True(t, consumed)
+	assert.False(t, m.lock.locked)
+	assert.Empty(t, m.lock.alert)
+}
+
+func TestInputWhileLocked(t *testing.T) {
+	m, page := newTestModel(lock{locked: true})
+	_, consumed := m.updateLock(tea.MouseMsg{})
+	assert.True(t, consumed)
+
+	cmd, consumed := m.updateLock(tea.KeyMsg{Type: tea.KeyCtrlC})
+	assert.True(t, consumed)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, 1, page.quits)
+
+	m.lock.locked = false
+	before := m.lock.lastActivity
+	_, consumed = m.updateLock(tea.KeyMsg{Type: tea.KeyDown})
+	assert.False(t, consumed)
+	assert.True(t, m.lock.lastActivity.After(before))
+}
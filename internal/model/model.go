@@ -1,12 +1,16 @@
 package model
 
 import (
+	"smm/internal/bootstrap"
 	"smm/internal/client"
+	"smm/internal/clipboard"
 	"smm/internal/config"
 	"smm/internal/page"
 	"smm/internal/view"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
 )
 
 type Page interface {
@@ -14,6 +18,10 @@ type Page interface {
 	View() string
 	Resize(int, int)
 	Update(cmd tea.Msg) tea.Cmd
+	SelectSecret(name string)
+	Lock()
+	Close()
+	Quit()
 }
 
 type Model struct {
@@ -22,11 +30,24 @@ type Model struct {
 	width     int
 	height    int
 	page      Page
+	lock      lock
+	clipboard *clipboard.Clipboard
 	ProjectId string
 }
 
 func New(projectId string) *Model {
-	return &Model{ProjectId: projectId}
+	return &Model{ProjectId: projectId, lock: newLock(), clipboard: newClipboard()}
+}
+
+// newClipboard copies with the configured provider, or the detected one
+// when it is not available.
+func newClipboard() *clipboard.Clipboard {
+	provider, err := clipboard.Detect(config.GetClipboardProvider(), clipboard.DefaultEnv())
+	if err != nil {
+		log.Error().Err(err).Msg("Error setting up the clipboard, detecting it instead")
+		provider, _ = clipboard.Detect(clipboard.Auto, clipboard.DefaultEnv())
+	}
+	return clipboard.New(provider, config.GetClipboardClearAfter())
 }
 
 func (m *Model) Init() tea.Cmd {
@@ -37,12 +58,16 @@ func (m *Model) Init() tea.Cmd {
 		m.page.Update(tea.KeyMsg{Runes: []rune("p"), Type: tea.KeyRunes})
 	}
 
-	return nil
+	return m.lock.tick()
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if cmd, consumed := m.updateLock(msg); consumed {
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case view.ProjectSelectedMessage:
 		err := m.setProjectId(msg.ProjectId)
@@ -50,6 +75,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if err != nil {
 			m.page.Update(view.ShowProjectSelectMsg{TextAlert: "Error setting project ID"})
+		} else if msg.SecretName != "" {
+			m.page.SelectSecret(msg.SecretName)
 		}
 		return m, nil
 	case tea.WindowSizeMsg:
@@ -72,7 +99,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *Model) initialize() {
 	var selected page.CurrentSecret
 
-	m.page = page.NewSecrets(m.gcp, selected.Index())
+	if m.page != nil {
+		m.page.Close()
+	}
+	m.page = page.NewSecrets(m.gcp, m.ProjectId, selected.Index(), m.clipboard)
 	m.page.Resize(m.width, m.height)
 }
 
@@ -86,6 +116,9 @@ func (m *Model) View() string {
 	if m.page == nil {
 		return "Error: Application not properly initialized. Please restart."
 	}
+	if m.lock.locked {
+		return view.LockScreen(m.width, m.height, m.lock.reason, m.lock.alert)
+	}
 
 	return m.page.View()
 }
@@ -96,16 +129,16 @@ func (m *Model) setProjectId(projectId string) error {
 		return nil
 	}
 
-	var err error
-	projectType := config.GetTypeByProjectId(projectId)
-	if projectType == "gcp" {
-		m.gcp, err = client.NewGcp(projectId)
-	} else {
-		m.gcp, err = client.NewFakeClient(projectId)
-	}
+	gcp, err := client.New(projectId, config.GetTypeByProjectId(projectId), bootstrap.ClientOptions(projectId))
 	if err != nil {
 		return err
 	}
+	if m.gcp != nil {
+		_ = m.gcp.Close()
+	}
+	m.gcp = gcp
 
+	config.TouchProject(projectId, time.Now())
+	_ = config.Save()
 	return nil
 }
@@ -0,0 +1,113 @@
+package page
+
+import (
+	"fmt"
+	"slices"
+	"smm/internal/client"
+	"smm/internal/view"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+// expandVersions lists the older versions of selected under it, newest
+// first whatever order the client returned them in. The latest version is the
+// secret row itself, which shows its aliases.
+func (s *Secrets) expandVersions(selected view.Secret, versions []client.Version) tea.Cmd {
+	index := s.components.list.RealIndex()
+	if len(versions) == 0 {
+		return nil
+	}
+	versions = slices.Clone(versions)
+	slices.SortFunc(versions, func(a, b client.Version) int { return b.Version - a.Version })
+
+	cmds := []tea.Cmd{s.components.list.SetAliases(index, versions[0].Aliases)}
+	versions = versions[1:]
+	s.components.toast.SetText(fmt.Sprintf("Secret has %v versions", len(versions)))
+	for i, version := range versions {
+		secret := view.NewSecret(strconv.Itoa(version.Version), version.FullPath, "version", version.Version, version.CreatedAt)
+		secret.SetRelated(&selected)
+		secret.SetProject(selected.Project())
+		secret.SetAliases(version.Aliases)
+		cmds = append(cmds, s.components.list.InsertItem(index+1+i, secret))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// selectedSecret returns the secret row of the selection, which is the
+// selection itself unless a version row is selected.
+func (s *Secrets) selectedSecret() view.Secret {
+	selected := s.components.list.SelectedItem()
+	if selected.Type() == "version" && selected.Related() != nil {
+		return *selected.Related()
+	}
+	return selected
+}
+
+// openAlias expands the versions of the selected secret and selects the one
+// alias points to.
+func (s *Secrets) openAlias(alias string) tea.Cmd {
+	secret := s.selectedSecret()
+	versions, err := s.clientFor(secret).GetSecretVersions(secret.FullPath())
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting secret versions")
+		s.components.toast.SetText("Error getting secret versions")
+		return nil
+	}
+
+	index := slices.IndexFunc(versions, func(version client.Version) bool {
+		return slices.Contains(version.Aliases, alias)
+	})
+	if index < 0 {
+		s.components.toast.SetText(fmt.Sprintf("Alias %s not found in %s", alias, secret.Title()))
+		return nil
+	}
+
+	aliased := versions[index].Version
+
+	s.components.list.DelVersionItems()
+	if !s.components.list.SelectPath(secret.FullPath()) {
+		return nil
+	}
+	cmd := s.expandVersions(s.components.list.SelectedItem(), versions)
+	if aliased != client.LatestVersion(versions) {
+		s.components.list.SelectVersion(secret.FullPath(), aliased)
+	}
+	s.components.toast.SetText(fmt.Sprintf("Alias %s points to version %d", alias, aliased))
+
+	return tea.Batch(cmd, s.showSecret())
+}
+
+// confirmAlias asks before pointing alias to the selected version.
+func (s *Secrets) confirmAlias(alias string) {
+	selected := s.components.list.SelectedItem()
+	secret := s.selectedSecret()
+	versions, err := s.gcp.GetSecretVersions(secret.FullPath())
+	if err != nil || len(versions) == 0 {
+		log.Error().Err(err).Msg("Error getting secret versions")
+		s.components.toast.SetText("Error getting secret versions")
+		return
+	}
+
+	version := client.LatestVersion(versions)
+	if selected.Type() == "version" {
+		version = selected.Version()
+	}
+
+	question := fmt.Sprintf("Add alias %s to version %d of %s?", alias, version, secret.Title())
+	for _, v := range versions {
+		if !slices.Contains(v.Aliases, alias) {
+			continue
+		}
+		if v.Version == version {
+			s.components.toast.SetText(fmt.Sprintf("Alias %s already points to version %d", alias, version))
+			return
+		}
+		question = fmt.Sprintf("Move alias %s of %s from version %d to %d?", alias, secret.Title(), v.Version, version)
+	}
+
+	s.Modal = s.confirmChange(question, secret.Title(), AssignAliasMsg{FullPath: secret.FullPath(), Alias: alias, Version: version})
+	s.Modal.Init()
+}
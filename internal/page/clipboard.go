@@ -0,0 +1,142 @@
+package page
+
+import (
+	"encoding/base64"
+	"fmt"
+	"smm/internal/client"
+	"smm/internal/payload"
+	"smm/internal/view"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+// ClipboardTickMsg counts down until the copy of Generation is cleared from
+// the clipboard.
+type ClipboardTickMsg struct {
+	Generation int
+}
+
+// CopyKeyMsg copies the Value of Key, picked from the keys of a secret.
+type CopyKeyMsg struct {
+	Key   string
+	Value string
+}
+
+func clipboardTick(generation int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return ClipboardTickMsg{Generation: generation}
+	})
+}
+
+// copySecret copies the selected secret or version in format. Copying a
+// single key opens a picker of the keys instead.
+func (s *Secrets) copySecret(format view.CopyFormat) tea.Cmd {
+	selected := s.components.list.SelectedItem()
+	version := 0
+	if selected.Type() == "version" {
+		version = selected.Version()
+	}
+	if format == view.CopyReference {
+		return s.copyToClipboard(client.Reference(selected.FullPath(), version), "Reference")
+	}
+
+	gcp := s.clientFor(selected)
+	var data []byte
+	var err error
+	if version > 0 {
+		data, err = gcp.GetSecretVersion(selected.FullPath(), strconv.Itoa(version))
+	} else {
+		data, err = gcp.GetSecret(selected.FullPath())
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting secret for clipboard")
+		s.components.toast.SetText("Error getting secret")
+		return nil
+	}
+
+	switch format {
+	case view.CopyKey:
+		_, entries := payload.Parse(data)
+		if len(entries) == 0 {
+			s.components.toast.SetText("Only env and JSON secrets have keys")
+			return nil
+		}
+		keys := make([]string, len(entries))
+		values := make(map[string]string, len(entries))
+		for i, entry := range entries {
+			keys[i] = entry.Key
+			values[entry.Key] = entry.Value
+		}
+		s.Modal = view.NewKeyPicker("Copy key: ", "enter: copy its value", keys, func(key string) tea.Msg {
+			return CopyKeyMsg{Key: key, Value: values[key]}
+		})
+		s.Modal.Init()
+		return nil
+	case view.CopyExport:
+		lines, err := payload.Export(data)
+		if err != nil {
+			s.components.toast.SetText("Cannot export: " + err.Error())
+			return nil
+		}
+		return s.copyToClipboard(lines, "Export lines")
+	case view.CopyBase64:
+		return s.copyToClipboard(base64.StdEncoding.EncodeToString(data), "Base64 value")
+	}
+
+	if version > 0 {
+		return s.copyToClipboard(string(data), fmt.Sprintf("Version %d", version))
+	}
+	return s.copyToClipboard(string(data), "Secret")
+}
+
+// copyToClipboard copies text, described by what in the toast, and starts
+// the countdown to clear it.
+func (s *Secrets) copyToClipboard(text, what string) tea.Cmd {
+	generation, err := s.clipboard.Copy(text, time.Now())
+	if err != nil {
+		log.Error().Err(err).Msgf("Error copying to clipboard with %s", s.clipboard.Name())
+		s.components.toast.SetText("Failed to copy to clipboard with " + s.clipboard.Name())
+		return nil
+	}
+	s.copiedWhat = what
+
+	remaining, pending := s.clipboard.Remaining(generation, time.Now())
+	if !pending {
+		s.components.toast.SetText(what + " copied to clipboard")
+		return nil
+	}
+	s.components.toast.SetText(fmt.Sprintf("%s copied to clipboard, clearing in %ds", what, int(remaining.Round(time.Second).Seconds())))
+	return clipboardTick(generation)
+}
+
+// clipboardTick shows the countdown and clears the clipboard once it ends,
+// unless something else was copied since.
+func (s *Secrets) clipboardTick(msg ClipboardTickMsg) tea.Cmd {
+	remaining, pending := s.clipboard.Remaining(msg.Generation, time.Now())
+	if !pending {
+		return nil
+	}
+	if remaining > 0 {
+		what := s.copiedWhat
+		if what == "" {
+			what = "Secret"
+		}
+		s.components.toast.SetText(fmt.Sprintf("%s copied to clipboard, clearing in %ds", what, int(remaining.Round(time.Second).Seconds())))
+		return clipboardTick(msg.Generation)
+	}
+
+	cleared, err := s.clipboard.Clear()
+	switch {
+	case err != nil:
+		log.Error().Err(err).Msg("Error clearing the clipboard")
+		s.components.toast.SetText("Failed to clear the clipboard")
+	case cleared:
+		s.components.toast.SetText("Clipboard cleared")
+	default:
+		s.components.toast.SetText("Clipboard changed since the copy, left as is")
+	}
+	return nil
+}
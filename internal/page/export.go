@@ -0,0 +1,132 @@
+package page
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"smm/internal/client"
+	"smm/internal/export"
+	"smm/internal/view"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+// exportTargets returns the rows marked for export or, when none is, the
+// selected one.
+func (s *Secrets) exportTargets() []view.Secret {
+	if marked := s.components.list.Marked(); len(marked) > 0 {
+		return marked
+	}
+	if selected := s.components.list.SelectedItem(); selected.FullPath() != "" {
+		return []view.Secret{selected}
+	}
+	return nil
+}
+
+// openExport asks how to export the marked secrets or the selected one.
+func (s *Secrets) openExport() {
+	targets := s.exportTargets()
+	if len(targets) == 0 {
+		s.components.toast.SetText("No secret selected")
+		return
+	}
+
+	target := fmt.Sprintf("%d marked secrets", len(targets))
+	if len(targets) == 1 {
+		target = targets[0].Title()
+		if targets[0].Type() == "version" {
+			target = fmt.Sprintf("version %d of %s", targets[0].Version(), targets[0].Related().Title())
+		}
+	}
+	s.Modal = view.NewExportForm(target)
+	s.Modal.Init()
+}
+
+// ExportFile is an export rendered and ready to be written to Path.
+type ExportFile struct {
+	Path       string
+	Content    []byte
+	Recipients []string
+	Names      []string
+}
+
+// ExportedMsg reports that File was written, or why it was not.
+type ExportedMsg struct {
+	File ExportFile
+	Err  error
+}
+
+// ExportOverwriteMsg asks to write File over the existing one once
+// confirmed.
+type ExportOverwriteMsg struct {
+	File ExportFile
+}
+
+// exportSecrets fetches the export targets and writes them as msg asks in
+// the background.
+func (s *Secrets) exportSecrets(msg view.ExportMessage) tea.Cmd {
+	type target struct {
+		c                       client.Client
+		name, fullPath, version string
+	}
+	var targets []target
+	for _, t := range s.exportTargets() {
+		name, version := t.Title(), ""
+		if t.Type() == "version" {
+			name, version = t.Related().Title(), strconv.Itoa(t.Version())
+		}
+		targets = append(targets, target{c: s.clientFor(t), name: name, fullPath: t.FullPath(), version: version})
+	}
+
+	s.components.toast.SetText("Exporting…")
+	return func() tea.Msg {
+		file := ExportFile{Path: msg.Path, Recipients: msg.Recipients}
+		var secrets []export.Secret
+		for _, t := range targets {
+			secret, err := export.Fetch(t.c, t.name, t.fullPath, t.version)
+			if err != nil {
+				return ExportedMsg{File: file, Err: fmt.Errorf("error getting secret %s: %w", t.name, err)}
+			}
+			secrets = append(secrets, secret)
+			file.Names = append(file.Names, t.name)
+		}
+
+		content, err := export.Render(msg.Format, secrets, msg.Name)
+		if err != nil {
+			return ExportedMsg{File: file, Err: err}
+		}
+		file.Content = content
+		return ExportedMsg{File: file, Err: export.WriteFile(file.Path, content, file.Recipients, false)}
+	}
+}
+
+// writeExport writes file over an existing one.
+func writeExport(file ExportFile) tea.Cmd {
+	return func() tea.Msg {
+		return ExportedMsg{File: file, Err: export.WriteFile(file.Path, file.Content, file.Recipients, true)}
+	}
+}
+
+// exported reports a finished export, unmarking what was exported, or asks
+// whether to overwrite the file it found in the way.
+func (s *Secrets) exported(msg ExportedMsg) {
+	if errors.Is(msg.Err, fs.ErrExist) {
+		s.Modal = view.NewConfirm(msg.File.Path+" already exists, overwrite it?", ExportOverwriteMsg{File: msg.File})
+		s.Modal.Init()
+		return
+	}
+	if msg.Err != nil {
+		log.Error().Err(msg.Err).Msg("Error exporting secrets")
+		s.components.toast.SetText("Export failed: " + msg.Err.Error())
+		return
+	}
+
+	s.components.list.ClearMarks()
+	if len(msg.File.Names) == 1 {
+		s.components.toast.SetText("Exported " + msg.File.Names[0] + " to " + msg.File.Path)
+	} else {
+		s.components.toast.SetText(fmt.Sprintf("Exported %d secrets to %s", len(msg.File.Names), msg.File.Path))
+	}
+}
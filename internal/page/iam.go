@@ -0,0 +1,90 @@
+package page
+
+import (
+	"fmt"
+	"path/filepath"
+	"smm/internal/client"
+	"smm/internal/ui"
+	"smm/internal/view"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+type SetIamPolicyMsg struct {
+	FullPath string
+	Policy   client.Policy
+}
+
+// openIam shows the IAM policy of the selected secret.
+func (s *Secrets) openIam() {
+	selected := s.selectedSecret()
+	if selected.FullPath() == "" {
+		s.components.toast.SetText("No secret selected")
+		return
+	}
+
+	policy, err := s.clientFor(selected).GetIamPolicy(selected.FullPath())
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting IAM policy")
+		s.components.toast.SetText("Error getting IAM policy")
+		return
+	}
+
+	modal := view.NewIamModal(selected.FullPath(), selected.Title(), policy)
+	if reason := s.deniedReason("I", selected); reason != "" {
+		modal.SetDenied(reason)
+	}
+	s.Modal = modal
+	s.Modal.Init()
+}
+
+// confirmIamChange previews the policy resulting from change and asks to
+// apply it. The policy is read again so that its etag is current.
+func (s *Secrets) confirmIamChange(change view.IamChangeMessage) {
+	if s.isForeign(s.selectedSecret()) {
+		s.components.toast.SetText("Press enter to switch to the secret's project first")
+		return
+	}
+	if s.protection == client.ProtectionReadOnly {
+		s.components.toast.SetText(fmt.Sprintf("Project %s is read-only", s.ProjectId))
+		return
+	}
+	if missing := s.permissions.missing(change.FullPath, []string{client.PermissionSetIamPolicy}); len(missing) > 0 {
+		s.components.toast.SetText(fmt.Sprintf("Missing %s on %s", missing[0], filepath.Base(change.FullPath)))
+		return
+	}
+
+	policy, err := s.gcp.GetIamPolicy(change.FullPath)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting IAM policy")
+		s.components.toast.SetText("Error getting IAM policy")
+		return
+	}
+
+	updated := policy.AddMember(change.Role, change.Member)
+	if change.Remove {
+		updated = policy.RemoveMember(change.Role, change.Member, change.Condition)
+	}
+
+	diff := client.DiffPolicy(policy, updated)
+	if len(diff) == 0 {
+		s.components.toast.SetText("No change to the IAM policy")
+		return
+	}
+
+	lines := make([]string, len(diff))
+	for i, line := range diff {
+		if strings.HasPrefix(line, "+") {
+			lines[i] = ui.StyleAdded().Render(line)
+		} else {
+			lines[i] = ui.StyleWarning().Render(line)
+		}
+	}
+
+	secretName := filepath.Base(change.FullPath)
+	confirm := s.confirmChange(fmt.Sprintf("Update the IAM policy of %s?", secretName), secretName, SetIamPolicyMsg{FullPath: change.FullPath, Policy: updated})
+	confirm.SetDetail(strings.Join(lines, "\n"))
+	s.Modal = confirm
+	s.Modal.Init()
+}
@@ -0,0 +1,194 @@
+package page
+
+import (
+	"fmt"
+	"smm/internal/client"
+	"smm/internal/importer"
+	"smm/internal/ui"
+	"smm/internal/view"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+// importPlanShown is how many steps of an import plan the confirmation
+// lists.
+const importPlanShown = 20
+
+// ImportPlanMsg asks to apply an import plan once confirmed.
+type ImportPlanMsg struct {
+	Steps  []importer.Step
+	Labels map[string]string
+}
+
+// ImportPlannedMsg carries the plan built for importing Path into ProjectId,
+// or why it could not be built.
+type ImportPlannedMsg struct {
+	ProjectId string
+	Path      string
+	Steps     []importer.Step
+	Labels    map[string]string
+	Err       error
+}
+
+// ImportStepMsg reports that the step at Index of the running import was
+// applied, or failed with Err.
+type ImportStepMsg struct {
+	Index int
+	Err   error
+}
+
+// importRun is an import being applied one step at a time, so that its
+// progress is drawn between them.
+type importRun struct {
+	gcp      client.Client
+	steps    []importer.Step
+	labels   map[string]string
+	progress *view.ImportProgress
+	failed   []bool
+}
+
+func (r *importRun) apply(index int) tea.Cmd {
+	gcp, step, labels := r.gcp, r.steps[index], r.labels
+	return func() tea.Msg {
+		return ImportStepMsg{Index: index, Err: importer.Apply(gcp, step, labels)}
+	}
+}
+
+// planImport reads the file or directory of msg and compares it with the
+// project in the background, as this reads every secret being imported.
+func (s *Secrets) planImport(msg view.ImportMessage) tea.Cmd {
+	gcp, projectId := s.gcp, s.ProjectId
+	s.components.toast.SetText("Reading " + msg.Path + "…")
+	return func() tea.Msg {
+		planned := ImportPlannedMsg{ProjectId: projectId, Path: msg.Path, Labels: msg.Labels}
+		items, err := importer.Read(msg.Path)
+		if err != nil {
+			planned.Err = err
+			return planned
+		}
+		if len(items) > 0 {
+			planned.Steps, planned.Err = importer.Plan(gcp, items)
+		}
+		return planned
+	}
+}
+
+// confirmImport asks to confirm what importing the planned file or
+// directory does.
+func (s *Secrets) confirmImport(msg ImportPlannedMsg) {
+	if msg.ProjectId != s.ProjectId {
+		return
+	}
+	if msg.Err != nil {
+		log.Error().Err(msg.Err).Msg("Error planning import")
+		s.components.toast.SetText("Cannot import: " + msg.Err.Error())
+		return
+	}
+	steps := msg.Steps
+	if len(steps) == 0 {
+		s.components.toast.SetText("Nothing to import in " + msg.Path)
+		return
+	}
+
+	question := fmt.Sprintf("Import %d secrets into %s?", len(steps), s.ProjectId)
+	confirm := s.confirmChange(question, s.ProjectId, ImportPlanMsg{Steps: steps, Labels: msg.Labels})
+	confirm.SetDetail(formatImportPlan(steps, msg.Labels))
+	s.Modal = confirm
+	s.Modal.Init()
+}
+
+// formatImportPlan lists what happens to each secret: + created, ~ new
+// version, = unchanged and ! not importable.
+func formatImportPlan(steps []importer.Step, labels map[string]string) string {
+	lines := []string{importer.Summary(steps)}
+	if len(labels) > 0 {
+		var pairs []string
+		for key, value := range labels {
+			pairs = append(pairs, key+"="+value)
+		}
+		sort.Strings(pairs)
+		lines = append(lines, ui.StyleLow().Render("Labels: "+strings.Join(pairs, ", ")))
+	}
+	lines = append(lines, "")
+
+	for i, step := range steps {
+		if i == importPlanShown {
+			lines = append(lines, ui.StyleLow().Render(fmt.Sprintf("… and %d more", len(steps)-importPlanShown)))
+			break
+		}
+		switch step.Action {
+		case importer.Create:
+			lines = append(lines, ui.StyleAdded().Render("+ "+step.Name))
+		case importer.Update:
+			lines = append(lines, ui.StyleWarning().Render("~ "+step.Name+" (new version)"))
+		case importer.Skip:
+			lines = append(lines, ui.StyleLow().Render("= "+step.Name+" (unchanged)"))
+		case importer.Invalid:
+			lines = append(lines, ui.StyleWarning().Render(fmt.Sprintf("! %s: %v", step.Name, step.Err)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// startImport applies a confirmed plan, showing its progress.
+func (s *Secrets) startImport(plan ImportPlanMsg) tea.Cmd {
+	s.importing = &importRun{
+		gcp:      s.gcp,
+		steps:    plan.Steps,
+		labels:   plan.Labels,
+		progress: view.NewImportProgress(len(plan.Steps)),
+		failed:   make([]bool, len(plan.Steps)),
+	}
+	s.Modal = s.importing.progress
+	return s.importing.apply(0)
+}
+
+// importStep records an applied step and applies the next one, reloading
+// the list once every step is done.
+func (s *Secrets) importStep(msg ImportStepMsg) tea.Cmd {
+	run := s.importing
+	if run == nil {
+		return nil
+	}
+
+	step := run.steps[msg.Index]
+	if msg.Err != nil {
+		log.Error().Err(msg.Err).Msgf("Error importing %s", step.Name)
+		run.failed[msg.Index] = true
+	}
+	run.progress.Advance(step.Name, msg.Err)
+	if next := msg.Index + 1; next < len(run.steps) {
+		return run.apply(next)
+	}
+
+	s.importing = nil
+	summary := importSummary(run)
+	run.progress.Finish(summary)
+	s.metadata.reset()
+	s.cancelSearch()
+	s.components.list.LoadSecrets(s.gcp)
+	s.components.toast.SetText("Import finished: " + summary)
+	return s.showSecret()
+}
+
+// importSummary counts the applied steps by what they did and the failed
+// ones apart.
+func importSummary(run *importRun) string {
+	var applied []importer.Step
+	failed := 0
+	for i, step := range run.steps {
+		if run.failed[i] {
+			failed++
+		} else {
+			applied = append(applied, step)
+		}
+	}
+	summary := importer.Summary(applied)
+	if failed > 0 {
+		summary += fmt.Sprintf(", %d failed", failed)
+	}
+	return summary
+}
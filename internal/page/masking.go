@@ -0,0 +1,69 @@
+package page
+
+import (
+	"fmt"
+	"smm/internal/payload"
+	"smm/internal/view"
+)
+
+// masking hides the values of the shown secret. What is revealed stays
+// visible until another secret is selected.
+type masking struct {
+	enabled bool
+	secret  string
+	all     bool
+	keys    map[string]bool
+	data    []byte
+}
+
+// secretKey identifies a secret or one of its versions.
+func secretKey(secret view.Secret) string {
+	return fmt.Sprintf("%s@%d", secret.FullPath(), secret.Version())
+}
+
+// follow hides everything again when secret is not the one revealed.
+func (m *masking) follow(secret view.Secret) {
+	if key := secretKey(secret); key != m.secret {
+		m.secret = key
+		m.all = false
+		m.keys = nil
+		m.data = nil
+	}
+}
+
+func (m *masking) reveal(key string) {
+	if m.keys == nil {
+		m.keys = map[string]bool{}
+	}
+	m.keys[key] = true
+}
+
+// keyNames returns the keys of the shown payload, empty unless it holds env
+// variables or JSON.
+func (m *masking) keyNames() []string {
+	_, entries := payload.Parse(m.data)
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Key
+	}
+	return names
+}
+
+// snapshot returns a copy safe to use while the page goes on revealing.
+func (m *masking) snapshot() masking {
+	keys := make(map[string]bool, len(m.keys))
+	for key := range m.keys {
+		keys[key] = true
+	}
+	return masking{enabled: m.enabled, all: m.all, keys: keys}
+}
+
+// apply masks data unless masking is off or the whole secret is revealed.
+func (m masking) apply(data []byte) []byte {
+	if !m.enabled || m.all {
+		return data
+	}
+	return payload.Mask(data, func(key string) bool {
+		return m.keys[key]
+	})
+}
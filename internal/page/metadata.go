@@ -0,0 +1,81 @@
+package page
+
+import (
+	"smm/internal/client"
+	"smm/internal/view"
+	"sync"
+)
+
+// metadataOnly is the browsing mode where moving through the list shows the
+// metadata of secrets and their value is only accessed once requested. A
+// request lasts until another secret is selected.
+type metadataOnly struct {
+	enabled bool
+	secret  string
+	fetch   bool
+}
+
+// follow forgets the request to fetch a value when secret is not the one it
+// was made for.
+func (m *metadataOnly) follow(secret view.Secret) {
+	if key := secretKey(secret); key != m.secret {
+		m.secret = key
+		m.fetch = false
+	}
+}
+
+// showsValue reports whether the value of the selected secret is accessed.
+func (m metadataOnly) showsValue() bool {
+	return !m.enabled || m.fetch
+}
+
+// metadataEntry is what the detail panel shows of a secret in metadata-only
+// mode.
+type metadataEntry struct {
+	info     client.SecretInfo
+	versions []client.Version
+}
+
+// metadataCache remembers the metadata read for each secret, so that moving
+// through the list does not read it again on every update.
+type metadataCache struct {
+	mu      sync.Mutex
+	entries map[string]metadataEntry
+}
+
+func newMetadataCache() *metadataCache {
+	return &metadataCache{entries: map[string]metadataEntry{}}
+}
+
+// reset forgets every secret, so that changes made since are seen.
+func (c *metadataCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]metadataEntry{}
+}
+
+// load returns the metadata of the secret at fullPath, reading it unless it
+// was read already. It is called from commands, off the update loop.
+func (c *metadataCache) load(gcp client.Client, fullPath string) (metadataEntry, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[fullPath]
+	c.mu.Unlock()
+	if ok {
+		return entry, nil
+	}
+
+	info, err := gcp.GetSecretInfo(fullPath)
+	if err != nil {
+		return metadataEntry{}, err
+	}
+	versions, err := gcp.GetSecretVersions(fullPath)
+	if err != nil {
+		return metadataEntry{}, err
+	}
+	entry = metadataEntry{info: info, versions: versions}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[fullPath] = entry
+	return entry, nil
+}
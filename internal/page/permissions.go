@@ -0,0 +1,163 @@
+package page
+
+import (
+	"fmt"
+	"slices"
+	"smm/internal/client"
+	"smm/internal/view"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// actionPermissions are the permissions each key needs on the selected
+// secret.
+var actionPermissions = map[string][]string{
+	"n": {client.PermissionAccessVersion, client.PermissionAddVersion},
+	"r": {client.PermissionAccessVersion, client.PermissionAddVersion},
+	"e": {client.PermissionUpdateSecret},
+	"A": {client.PermissionUpdateSecret},
+	"I": {client.PermissionSetIamPolicy},
+}
+
+// projectActionPermissions are the permissions each key needs on the
+// project.
+var projectActionPermissions = map[string][]string{
+	"N": {client.PermissionCreateSecret},
+	"U": {client.PermissionCreateSecret},
+}
+
+// permissionEntry holds the permissions granted on a resource. They are
+// unknown when testing them failed.
+type permissionEntry struct {
+	granted []string
+	known   bool
+}
+
+// permissionCache remembers the permissions tested on each resource, so that
+// they are only asked once per page load.
+type permissionCache struct {
+	mu      sync.Mutex
+	entries map[string]permissionEntry
+}
+
+func newPermissionCache() *permissionCache {
+	return &permissionCache{entries: map[string]permissionEntry{}}
+}
+
+// reset forgets every tested permission, so that grants made since are seen.
+func (c *permissionCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]permissionEntry{}
+}
+
+// load tests permissions on resource unless it was tested already. It is
+// called from commands, off the update loop.
+func (c *permissionCache) load(gcp client.Client, resource string, permissions []string) {
+	c.mu.Lock()
+	_, ok := c.entries[resource]
+	c.mu.Unlock()
+	if ok || gcp == nil {
+		return
+	}
+
+	granted, err := gcp.TestIamPermissions(resource, permissions)
+	entry := permissionEntry{granted: granted, known: err == nil}
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not test permissions on %s", resource)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[resource] = entry
+}
+
+// missing returns the permissions known not to be granted on resource.
+// Nothing is missing while they are unknown, so that actions still work
+// when testing is not allowed.
+func (c *permissionCache) missing(resource string, permissions []string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[resource]
+	if !ok || !entry.known {
+		return nil
+	}
+
+	var missing []string
+	for _, permission := range permissions {
+		if !slices.Contains(entry.granted, permission) {
+			missing = append(missing, permission)
+		}
+	}
+	return missing
+}
+
+func (s *Secrets) projectResource() string {
+	return "projects/" + s.ProjectId
+}
+
+// deniedReason explains why the action bound to key cannot be performed on
+// selected, or returns "" when it can or it is not known.
+func (s *Secrets) deniedReason(key string, selected view.Secret) string {
+	resource, name := selected.FullPath(), selected.Title()
+	permissions, ok := actionPermissions[key]
+	if selected.Type() == "version" {
+		name = selected.Related().Title()
+	}
+	if !ok {
+		resource, name = s.projectResource(), s.ProjectId
+		permissions = projectActionPermissions[key]
+	}
+	if resource == "" || len(permissions) == 0 {
+		return ""
+	}
+	if s.protection == client.ProtectionReadOnly && !s.isForeign(selected) {
+		return fmt.Sprintf("Project %s is read-only", s.ProjectId)
+	}
+
+	missing := s.permissions.missing(resource, permissions)
+	if len(missing) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Missing %s on %s", strings.Join(missing, ", "), name)
+}
+
+// denyAction shows why the action bound to key cannot be performed on
+// selected, reporting whether it was denied.
+func (s *Secrets) denyAction(key string, selected view.Secret) bool {
+	reason := s.deniedReason(key, selected)
+	if reason == "" {
+		return false
+	}
+	s.components.toast.SetText(reason)
+	return true
+}
+
+// refreshDenied annotates the help with the actions denied on selected.
+func (s *Secrets) refreshDenied(selected view.Secret) {
+	denied := map[string]string{}
+	for key := range actionPermissions {
+		if reason := s.deniedReason(key, selected); reason != "" {
+			denied[key] = reason
+		}
+	}
+	for key := range projectActionPermissions {
+		if reason := s.deniedReason(key, selected); reason != "" {
+			denied[key] = reason
+		}
+	}
+	s.components.help.SetDenied(denied)
+}
+
+// confirmChange asks to confirm a change to target, a secret name or the
+// project ID. Projects protected with confirm-typed ask to type target.
+func (s *Secrets) confirmChange(question, target string, msg any) *view.Confirm {
+	confirm := view.NewConfirm(question, msg)
+	if s.protection == client.ProtectionConfirmTyped {
+		confirm.RequireTyped(target)
+	}
+	return confirm
+}
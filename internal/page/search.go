@@ -0,0 +1,303 @@
+package page
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"smm/internal/bootstrap"
+	"smm/internal/client"
+	"smm/internal/config"
+	"smm/internal/search"
+	"smm/internal/ui"
+	"smm/internal/view"
+	"sort"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// deepSearch holds the state of a search streaming its results from one
+// background goroutine per project.
+type deepSearch struct {
+	project  string
+	query    *search.Query
+	ctx      context.Context
+	cancel   context.CancelFunc
+	events   chan SearchEventMsg
+	mu       sync.Mutex
+	errs     map[string]error
+	progress map[string]client.SearchEvent
+	clients  map[string]client.Client
+	found    int
+	failed   int
+}
+
+// SearchEventMsg carries an event of the search in Project.
+type SearchEventMsg struct {
+	search  *deepSearch
+	Project string
+	Event   client.SearchEvent
+}
+
+type SearchDoneMsg struct {
+	search *deepSearch
+}
+
+// newDeepSearch searches every project in projects. The current project
+// reuses gcp, the others get a client of their own.
+func newDeepSearch(gcp client.Client, currentProject string, projects []string, query *search.Query, allVersions bool) *deepSearch {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &deepSearch{
+		project:  currentProject,
+		query:    query,
+		ctx:      ctx,
+		cancel:   cancel,
+		events:   make(chan SearchEventMsg),
+		errs:     map[string]error{},
+		progress: map[string]client.SearchEvent{},
+		clients:  map[string]client.Client{currentProject: gcp},
+	}
+	opts := client.SearchOptions{Parallelism: config.GetSearchParallelism(), AllVersions: allVersions}
+
+	var wg sync.WaitGroup
+	for _, project := range projects {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			projectClient := gcp
+			if project != currentProject {
+				var err error
+				projectClient, err = client.New(project, config.GetTypeByProjectId(project), bootstrap.ClientOptions(project))
+				if err != nil {
+					d.setErr(project, err)
+					return
+				}
+				if !d.setClient(project, projectClient) {
+					return
+				}
+			}
+
+			events := make(chan client.SearchEvent)
+			go func() {
+				defer close(events)
+				d.setErr(project, projectClient.SearchInSecrets(ctx, query, opts, events))
+			}()
+
+			for event := range events {
+				select {
+				case d.events <- SearchEventMsg{search: d, Project: project, Event: event}:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(d.events)
+	}()
+
+	return d
+}
+
+func (d *deepSearch) setErr(project string, err error) {
+	if err == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.errs[project] = err
+}
+
+// setClient registers the client opened for project so that close releases
+// it. It closes the client instead and returns false once the search was
+// closed.
+func (d *deepSearch) setClient(project string, projectClient client.Client) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.ctx.Err() != nil {
+		_ = projectClient.Close()
+		return false
+	}
+	d.clients[project] = projectClient
+	return true
+}
+
+// client returns the client of project, if the search opened one.
+func (d *deepSearch) client(project string) (client.Client, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	projectClient, ok := d.clients[project]
+	return projectClient, ok
+}
+
+// wait returns a command that delivers the next event of the search, or a
+// SearchDoneMsg once the search has finished.
+func (d *deepSearch) wait() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-d.events
+		if !ok {
+			return SearchDoneMsg{search: d}
+		}
+		return msg
+	}
+}
+
+// searchProjects returns the projects a search must cover: the current one,
+// every configured project when allProjects is set, and those named in the
+// query.
+func (s *Secrets) searchProjects(query *search.Query, allProjects bool) []string {
+	projects := []string{s.ProjectId}
+	if allProjects {
+		projects = append(projects, config.GetProjectIDs()...)
+	}
+	projects = append(projects, query.Projects()...)
+
+	sort.Strings(projects)
+	return slices.Compact(projects)
+}
+
+func (s *Secrets) startSearch(query *search.Query, allVersions, allProjects bool) tea.Cmd {
+	s.resetSearch()
+	projects := s.searchProjects(query, allProjects)
+	s.components.list.StartSearch(query.String(), len(projects) > 1)
+	s.search = newDeepSearch(s.gcp, s.ProjectId, projects, query, allVersions)
+	return s.search.wait()
+}
+
+// searchQuery returns the query of the last deep search, if its results are
+// still listed.
+func (s *Secrets) searchQuery() *search.Query {
+	if s.search == nil || s.components.list.SearchQuery == "" {
+		return nil
+	}
+	return s.search.query
+}
+
+// searchHighlights returns the lines of the rendered secret matched by query.
+// shown is the payload as displayed, which masking may have collapsed into a
+// single line that no longer lines up with data.
+func searchHighlights(query *search.Query, data, shown []byte) []int {
+	if query == nil || bytes.Count(data, []byte("\n")) != bytes.Count(shown, []byte("\n")) {
+		return nil
+	}
+	return ui.WrappedLines(shown, query.Highlights(data))
+}
+
+// clientFor returns the client of the project the secret was listed from.
+func (s *Secrets) clientFor(secret view.Secret) client.Client {
+	if s.search != nil && s.components.list.SearchQuery != "" {
+		if projectClient, ok := s.search.client(secret.Project()); ok {
+			return projectClient
+		}
+	}
+	return s.gcp
+}
+
+// isForeign reports whether the secret is a search result from a project
+// other than the current one.
+func (s *Secrets) isForeign(secret view.Secret) bool {
+	return secret.Project() != "" && secret.Project() != s.ProjectId
+}
+
+func (s *Secrets) cancelSearch() {
+	if s.search != nil {
+		s.search.cancel()
+	}
+}
+
+// resetSearch drops the last search so that none of its pending messages
+// apply to the list anymore.
+func (s *Secrets) resetSearch() {
+	if s.search != nil {
+		s.search.close()
+		s.search = nil
+	}
+}
+
+// close cancels the search and closes the clients it opened for projects
+// other than the one it was started from, once its results are no longer
+// listed.
+func (d *deepSearch) close() {
+	d.cancel()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for project, projectClient := range d.clients {
+		if project != d.project {
+			_ = projectClient.Close()
+		}
+	}
+}
+
+func (s *Secrets) handleSearchEvent(msg SearchEventMsg) tea.Cmd {
+	if msg.search != s.search {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	event := msg.Event
+	s.search.progress[msg.Project] = event
+
+	var scanned, total int
+	for _, progress := range s.search.progress {
+		scanned += progress.Scanned
+		total += progress.Total
+	}
+	s.components.list.SetSearchProgress(scanned, total)
+
+	if event.Err != nil && s.search.ctx.Err() == nil {
+		s.search.failed++
+	}
+
+	if event.Match != nil {
+		s.search.found++
+		cmds = append(cmds, s.components.list.AddSearchResult(*event.Match, event.Versions))
+		if s.search.found == 1 {
+			if len(event.Versions) > 0 {
+				s.components.list.SelectVersion(event.Match.FullPath, event.Versions[0].Version)
+			}
+			cmds = append(cmds, s.showSecret())
+		}
+	}
+
+	cmds = append(cmds, s.search.wait())
+	return tea.Batch(cmds...)
+}
+
+func (s *Secrets) handleSearchDone(msg SearchDoneMsg) {
+	if msg.search != s.search {
+		return
+	}
+
+	search := s.search
+	s.components.list.FinishSearch()
+	scanned, total := s.components.list.SearchProgress()
+
+	var failedProjects []string
+	for project, err := range search.errs {
+		if !errors.Is(err, context.Canceled) {
+			failedProjects = append(failedProjects, project)
+		}
+	}
+	sort.Strings(failedProjects)
+
+	var text string
+	switch {
+	case search.ctx.Err() != nil:
+		text = fmt.Sprintf("Search canceled: %d found (%d/%d scanned)", search.found, scanned, total)
+	case len(failedProjects) == 1 && len(search.progress) == 0:
+		text = "Search failed: " + search.errs[failedProjects[0]].Error()
+	case len(failedProjects) > 0:
+		text = fmt.Sprintf("Search finished: %d found, failed in %s", search.found, strings.Join(failedProjects, ", "))
+	default:
+		text = fmt.Sprintf("Search finished: %d found", search.found)
+	}
+	if search.failed > 0 {
+		text += fmt.Sprintf(", %d unreadable", search.failed)
+	}
+
+	s.components.toast.SetText(text)
+}
@@ -4,23 +4,37 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"smm/internal/bootstrap"
 	"smm/internal/client"
+	"smm/internal/clipboard"
+	"smm/internal/config"
 	"smm/internal/editor"
 	"smm/internal/ui"
 	"smm/internal/view"
 	"strconv"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
 	"github.com/rs/zerolog/log"
-	"github.com/tiagomelo/go-clipboard/clipboard"
 )
 
 type Secrets struct {
-	gcp        client.Client
-	components secretsComponents
-	Modal      view.Modal
-	ListWidth  int
+	gcp          client.Client
+	ProjectId    string
+	components   secretsComponents
+	Modal        view.Modal
+	ListWidth    int
+	search       *deepSearch
+	permissions  *permissionCache
+	protection   string
+	masking      masking
+	metadataOnly metadataOnly
+	metadata     *metadataCache
+	clipboard    *clipboard.Clipboard
+	copiedWhat   string
+	importing    *importRun
 }
 
 type CurrentSecret struct {
@@ -34,6 +48,7 @@ type secretsComponents struct {
 	detail *view.SecretView
 	help   *view.Help
 	toast  *view.Toast
+	banner *view.Banner
 }
 
 type RestoreSecretMsg struct {
@@ -42,6 +57,17 @@ type RestoreSecretMsg struct {
 	Version  int
 }
 
+type UpdateSettingsMsg struct {
+	FullPath string
+	Settings client.SecretSettings
+}
+
+type AssignAliasMsg struct {
+	FullPath string
+	Alias    string
+	Version  int
+}
+
 func (S CurrentSecret) Name() string {
 	return S.name
 }
@@ -55,10 +81,11 @@ func (S CurrentSecret) Index() int {
 }
 
 func (s *Secrets) View() string {
-	borderedList := ui.StyleBorder(s.components.list.IsFocused).
+	accent := s.components.banner.Accent()
+	borderedList := ui.StyleAccentBorder(s.components.list.IsFocused, accent).
 		Width(s.components.list.Width()).
 		Render(s.components.list.View())
-	borderedDetail := ui.StyleBorder(s.components.detail.IsFocused).
+	borderedDetail := ui.StyleAccentBorder(s.components.detail.IsFocused, accent).
 		Render(s.components.detail.View())
 
 	borderedHelp := ui.StyleLowBorder().
@@ -76,6 +103,17 @@ func (s *Secrets) View() string {
 		borderedList = ui.PlaceOverlay(x, 0, listTitle, borderedList, false)
 	}
 
+	if s.components.list.IsSearching() {
+		scanned, total := s.components.list.SearchProgress()
+		progress := ui.StyleBorderTitle().Render(fmt.Sprintf(" Searching %d/%d ", scanned, total))
+		x = (s.components.list.Width() - lipgloss.Width(progress)) / 2
+		borderedList = ui.PlaceOverlay(x, lipgloss.Height(borderedList)-1, progress, borderedList, false)
+	} else if s.gcp != nil && s.gcp.Filter() != "" {
+		filter := ui.StyleBorderTitle().Render(truncate.StringWithTail(" "+s.gcp.Filter()+" ", uint(max(s.components.list.Width()-2, 0)), "… "))
+		x = (s.components.list.Width() - lipgloss.Width(filter)) / 2
+		borderedList = ui.PlaceOverlay(x, lipgloss.Height(borderedList)-1, filter, borderedList, false)
+	}
+
 	if s.components.detail.IsFiltering {
 		x = (s.components.detail.Width() - len(s.components.detail.FilterValue)) / 2
 		detailTitle := ui.StyleBorderTitle().Render(s.components.detail.FilterValue)
@@ -88,6 +126,7 @@ func (s *Secrets) View() string {
 
 	render := lipgloss.JoinVertical(
 		lipgloss.Top,
+		s.components.banner.View(),
 		lipgloss.JoinHorizontal(lipgloss.Top, borderedList, borderedDetail),
 		lipgloss.JoinHorizontal(lipgloss.Bottom, borderedHelp),
 		lipgloss.JoinHorizontal(lipgloss.Bottom, s.components.toast.View()),
@@ -102,6 +141,9 @@ func (s *Secrets) View() string {
 
 func (s *Secrets) Resize(width int, height int) {
 
+	// The banner takes the first line.
+	height--
+
 	if s.components.list.IsFiltering() {
 		s.components.list.SetHeight(height - 12)
 	} else {
@@ -112,6 +154,7 @@ func (s *Secrets) Resize(width int, height int) {
 	s.components.detail.SetWidth(width - 5 - s.components.list.Width())
 	s.components.detail.SetHeight(height - 6)
 	s.components.help.SetWidth(s.components.list.Width() + s.components.detail.Width() + 2)
+	s.components.banner.SetWidth(s.components.list.Width() + s.components.detail.Width() + 4)
 	s.components.toast.SetWith(width)
 }
 
@@ -124,30 +167,195 @@ func (s *Secrets) Update(msg tea.Msg) tea.Cmd {
 		s.components.toast.SetText(msg.Text)
 		return nil
 	case view.ShowProjectSelectMsg:
-		modal := view.NewProjectSelectorModal()
+		modal := s.newProjectSelector()
 		modal.SetAlert(msg.TextAlert)
 		s.Modal = modal
 		s.Modal.Init()
+	case ClipboardTickMsg:
+		return s.clipboardTick(msg)
+	case view.CopyMessage:
+		s.Modal = nil
+		return s.copySecret(msg.Format)
+	case CopyKeyMsg:
+		s.Modal = nil
+		return s.copyToClipboard(msg.Value, msg.Key)
+	case view.ExportMessage:
+		s.Modal = nil
+		return s.exportSecrets(msg)
+	case ExportedMsg:
+		s.exported(msg)
+		return nil
+	case view.ImportMessage:
+		s.Modal = nil
+		if s.gcp == nil {
+			return nil
+		}
+		return s.planImport(msg)
+	case ImportPlannedMsg:
+		s.confirmImport(msg)
+		return nil
+	case ImportStepMsg:
+		return s.importStep(msg)
+	case SearchEventMsg:
+		return s.handleSearchEvent(msg)
+	case SearchDoneMsg:
+		s.handleSearchDone(msg)
+		return nil
 	case view.SearchMessage:
-		s.components.list.DeepSearch(msg.Query, s.gcp)
 		s.Modal = nil
+		if s.gcp == nil {
+			return nil
+		}
 		s.components.detail.SetFilteredValue(msg.Query)
+		if msg.Parsed == nil {
+			s.cancelSearch()
+			s.components.list.LoadSecrets(s.gcp)
+		} else {
+			cmds = append(cmds, s.startSearch(msg.Parsed, msg.AllVersions, msg.AllProjects))
+		}
+	case view.FilterMessage:
+		s.Modal = nil
+		if s.gcp == nil {
+			return nil
+		}
+		s.applyFilter(msg.Filter, msg.Save)
+	case view.CreateSecretMessage:
+		s.Modal = nil
+		if s.gcp == nil {
+			return nil
+		}
+		var settings client.SecretSettings
+		if msg.TTL > 0 {
+			settings.ExpireTime = time.Now().Add(msg.TTL).Truncate(time.Second)
+		}
+		cmd, err := editor.EditNewSecret(msg.Name, settings)
+		if err != nil {
+			log.Error().Err(err).Msg("Error writing new secret file")
+			s.components.toast.SetText("Failed to create temporary file")
+			return nil
+		}
+		return cmd
+	case view.IamChangeMessage:
+		s.Modal = nil
+		if s.gcp == nil {
+			return nil
+		}
+		s.confirmIamChange(msg)
+		return nil
+	case view.RevealKeyMessage:
+		s.Modal = nil
+		s.masking.reveal(msg.Key)
+		s.components.toast.SetText(msg.Key + " revealed")
+		return s.showSecret()
+	case view.AliasMessage:
+		s.Modal = nil
+		if s.gcp == nil {
+			return nil
+		}
+		if msg.Assign {
+			s.confirmAlias(msg.Alias)
+			return nil
+		}
+		return s.openAlias(msg.Alias)
 	case view.ConfirmationResultMessage:
+		if msg.Result {
+			s.metadata.reset()
+		}
 		switch msg.Msg.(type) {
+		case ExportOverwriteMsg:
+			s.Modal = nil
+			if !msg.Result {
+				s.components.toast.SetText("Export canceled")
+				return nil
+			}
+			return writeExport(msg.Msg.(ExportOverwriteMsg).File)
+		case ImportPlanMsg:
+			if !msg.Result {
+				s.Modal = nil
+				s.components.toast.SetText("Import canceled")
+				return nil
+			}
+			return s.startImport(msg.Msg.(ImportPlanMsg))
+		case UpdateSettingsMsg:
+			s.Modal = nil
+			updateMessage := msg.Msg.(UpdateSettingsMsg)
+			if !msg.Result {
+				s.components.toast.SetText("Settings unchanged")
+				return nil
+			}
+			err := s.gcp.UpdateSecretSettings(updateMessage.FullPath, updateMessage.Settings)
+			if err != nil {
+				log.Error().Err(err).Msg("Error updating secret settings")
+				s.components.toast.SetText("Error updating settings")
+				return nil
+			}
+			s.components.toast.SetText("Settings updated")
+			return s.components.list.SetSettings(updateMessage.FullPath, updateMessage.Settings)
+		case SetIamPolicyMsg:
+			s.Modal = nil
+			setMessage := msg.Msg.(SetIamPolicyMsg)
+			if !msg.Result {
+				s.components.toast.SetText("IAM policy unchanged")
+				s.openIam()
+				return nil
+			}
+			err := s.gcp.SetIamPolicy(setMessage.FullPath, setMessage.Policy)
+			if err != nil {
+				log.Error().Err(err).Msg("Error setting IAM policy")
+				s.components.toast.SetText("Error updating IAM policy")
+				return nil
+			}
+			s.openIam()
+			s.components.toast.SetText("IAM policy updated")
+			return nil
+		case AssignAliasMsg:
+			s.Modal = nil
+			assignMessage := msg.Msg.(AssignAliasMsg)
+			if !msg.Result {
+				s.components.toast.SetText("Alias unchanged")
+				return nil
+			}
+			err := s.gcp.SetVersionAlias(assignMessage.FullPath, assignMessage.Alias, assignMessage.Version)
+			if err != nil {
+				log.Error().Err(err).Msg("Error setting version alias")
+				s.components.toast.SetText("Error setting alias")
+				return nil
+			}
+			return s.openAlias(assignMessage.Alias)
+		case editor.NewSecretEditedMsg:
+			s.Modal = nil
+			createMessage := msg.Msg.(editor.NewSecretEditedMsg)
+			if !msg.Result {
+				s.components.toast.SetText("Secret not created")
+				return nil
+			}
+			secretInfo, err := s.gcp.CreateSecret(createMessage.SecretName, createMessage.Settings, createMessage.SecretData)
+			if err != nil {
+				log.Error().Err(err).Msg("Error creating secret")
+				s.components.toast.SetText("Error creating secret")
+				return nil
+			}
+			s.cancelSearch()
+			s.components.list.LoadSecrets(s.gcp)
+			s.components.list.SelectPath(secretInfo.FullPath)
+			s.components.toast.SetText("Secret created")
+			return s.showSecret()
 		case editor.EditFinishedMsg:
 			s.Modal = nil
 			newVersionMessage := msg.Msg.(editor.EditFinishedMsg)
 			log.Info().Msgf("Confirmation result in secrets: %v", msg.Result)
 			if msg.Result {
-				var title string
+				var title, fullPath string
 				if newVersionMessage.CurrentSecret.Type() == "version" {
 					title = newVersionMessage.CurrentSecret.Related().Title()
+					fullPath = newVersionMessage.CurrentSecret.Related().FullPath()
 				} else {
 					title = newVersionMessage.CurrentSecret.Title()
+					fullPath = newVersionMessage.CurrentSecret.FullPath()
 				}
 				log.Info().Msg("Creating new secret")
 				log.Info().Msgf("Creating new secret based on %v", title)
-				err := s.gcp.AddSecretVersion(title, newVersionMessage.SecretData)
+				err := s.gcp.AddSecretVersion(fullPath, newVersionMessage.SecretData)
 				if err != nil {
 					log.Error().Msgf("Error creating new secret: %v", err)
 				}
@@ -165,7 +373,7 @@ func (s *Secrets) Update(msg tea.Msg) tea.Cmd {
 					return nil
 				}
 				log.Info().Msg("Restoring secret")
-				err = s.gcp.AddSecretVersion(restoreMessage.Title, secretData)
+				err = s.gcp.AddSecretVersion(restoreMessage.FullPath, secretData)
 				if err != nil {
 					log.Error().Msgf("Error creating new secret: %v", err)
 					s.components.toast.SetText("Error restoring secret")
@@ -184,6 +392,13 @@ func (s *Secrets) Update(msg tea.Msg) tea.Cmd {
 			if s.components.list.IsFiltering() == false && s.components.detail.IsFiltering == false {
 				switch msg.String() {
 				case "n":
+					if s.isForeign(s.components.list.SelectedItem()) {
+						s.components.toast.SetText("Press enter to switch to the secret's project first")
+						return nil
+					}
+					if s.denyAction("n", s.components.list.SelectedItem()) {
+						return nil
+					}
 					tempDir := os.TempDir()
 					hash := s.components.list.SelectedItem().Hash()
 					filename := filepath.Join(tempDir, hash)
@@ -221,67 +436,97 @@ func (s *Secrets) Update(msg tea.Msg) tea.Cmd {
 					}
 
 					return editor.OpenEditor(secretData, s.components.list.SelectedItem())
+				case "N":
+					if s.denyAction("N", s.components.list.SelectedItem()) {
+						return nil
+					}
+					if s.gcp != nil {
+						s.Modal = view.NewCreateSecretForm()
+						s.Modal.Init()
+					}
 				case "r":
+					if s.isForeign(s.components.list.SelectedItem()) {
+						s.components.toast.SetText("Press enter to switch to the secret's project first")
+						return nil
+					}
 					if s.components.list.SelectedItem().Type() == "current" {
 						s.components.toast.SetText("Cannot restore current version")
 						return nil
 					}
+					if s.denyAction("r", s.components.list.SelectedItem()) {
+						return nil
+					}
 					s.components.toast.SetText(fmt.Sprintf("Restoring version"))
 					msg := RestoreSecretMsg{
 						FullPath: s.components.list.SelectedItem().Related().FullPath(),
 						Title:    s.components.list.SelectedItem().Related().Title(),
 						Version:  s.components.list.SelectedItem().Version(),
 					}
-					s.Modal = view.NewConfirm("Do you want to restore this secret version?", msg)
+					s.Modal = s.confirmChange("Do you want to restore this secret version?", msg.Title, msg)
 					s.Modal.Init()
 				case "p":
-					s.Modal = view.NewProjectSelectorModal()
+					s.Modal = s.newProjectSelector()
 					s.Modal.Init()
 				case "ctrl+c":
+					s.Quit()
 					return tea.Quit
 				case "v":
 					selected := s.components.list.SelectedItem()
 					if selected.Type() == "current" {
 						deleted := s.components.list.DelVersionItems()
 						if !deleted {
-							versions, err := s.gcp.GetSecretVersions(selected.FullPath())
+							versions, err := s.clientFor(selected).GetSecretVersions(selected.FullPath())
 							if err != nil {
 								log.Error().Err(err).Msg("Error getting secret versions")
 								s.components.toast.SetText("Error getting secret versions")
 								return nil
 							}
-							versions = versions[1:]
-							s.components.toast.SetText(fmt.Sprintf("Secret has %v versions", len(versions)))
-							for i, version := range versions {
-								secret := view.NewSecret(strconv.Itoa(version.Version), version.FullPath, "version", version.Version, version.CreatedAt)
-								secret.SetRelated(&selected)
-								cmd = s.components.list.InsertItem(s.components.list.RealIndex()+1+i, secret)
-							}
+							cmd = s.expandVersions(selected, versions)
 						}
 					}
 					s.components.list.Select(selected.Index())
 					return cmd
 				case "c":
-					secretName := s.components.list.SelectedItem().FullPath()
-					data, err := s.gcp.GetSecret(secretName)
-					if err != nil {
-						log.Error().Err(err).Msg("Error getting secret for clipboard")
-						s.components.toast.SetText("Error getting secret")
+					selected := s.components.list.SelectedItem()
+					if selected.FullPath() == "" {
+						s.components.toast.SetText("No secret selected")
+						return nil
+					}
+					target := "latest version of " + selected.Title()
+					if selected.Type() == "version" {
+						target = fmt.Sprintf("version %d of %s", selected.Version(), selected.Related().Title())
+					}
+					s.Modal = view.NewCopyMenu(target)
+					s.Modal.Init()
+				case " ":
+					if s.components.list.ToggleMark() {
+						s.components.toast.SetText(fmt.Sprintf("%d marked, press x to export", len(s.components.list.Marked())))
 					} else {
-						secretData := string(data)
-						c := clipboard.New()
-						err = c.CopyText(secretData)
-						if err != nil {
-							log.Error().Err(err).Msg("Error copying to clipboard")
-							s.components.toast.SetText("Failed to copy to clipboard")
-						} else {
-							s.components.toast.SetText("Secret copied to clipboard")
-						}
+						s.components.toast.SetText(fmt.Sprintf("%d marked", len(s.components.list.Marked())))
+					}
+					return nil
+				case "x":
+					s.openExport()
+				case "U":
+					if s.denyAction("U", s.components.list.SelectedItem()) {
+						return nil
+					}
+					if s.importing != nil {
+						s.components.toast.SetText("An import is already running")
+						return nil
+					}
+					if s.gcp != nil {
+						s.Modal = view.NewImportForm()
+						s.Modal.Init()
 					}
 				case "?":
-					s.Modal = view.NewProjectSelectorModal()
+					s.Modal = s.newProjectSelector()
 					s.Modal.Init()
 				case "esc":
+					if s.components.list.IsSearching() {
+						s.cancelSearch()
+						return nil
+					}
 					s.Init()
 					resizeCmd := func() tea.Msg {
 						return view.ResizeMessage{}
@@ -290,18 +535,139 @@ func (s *Secrets) Update(msg tea.Msg) tea.Cmd {
 					s.components.toast.SetText("Secrets refreshed")
 					s.components.list.SearchQuery = ""
 					s.components.detail.SetFilteredValue("")
+				case "enter":
+					selected := s.components.list.SelectedItem()
+					if s.components.list.IsFocused && s.isForeign(selected) {
+						name := selected.Title()
+						if selected.Type() == "version" {
+							name = selected.Related().Title()
+						}
+						config.SetSelectedProject(selected.Project())
+						_ = config.Save()
+						return func() tea.Msg {
+							return view.ProjectSelectedMessage{ProjectId: selected.Project(), SecretName: name}
+						}
+					}
+				case "a", "A":
+					selected := s.components.list.SelectedItem()
+					assign := msg.String() == "A"
+					if selected.FullPath() == "" {
+						s.components.toast.SetText("No secret selected")
+						return nil
+					}
+					if assign && s.isForeign(selected) {
+						s.components.toast.SetText("Press enter to switch to the secret's project first")
+						return nil
+					}
+					if assign && s.denyAction("A", selected) {
+						return nil
+					}
+					target := "latest version"
+					if selected.Type() == "version" {
+						target = "version " + selected.Title()
+					}
+					s.Modal = view.NewAliasForm(target, assign)
+					s.Modal.Init()
+				case "e":
+					selected := s.selectedSecret()
+					if selected.FullPath() == "" {
+						s.components.toast.SetText("No secret selected")
+						return nil
+					}
+					if s.isForeign(selected) {
+						s.components.toast.SetText("Press enter to switch to the secret's project first")
+						return nil
+					}
+					if s.denyAction("e", selected) {
+						return nil
+					}
+					secretInfo, err := s.gcp.GetSecretInfo(selected.FullPath())
+					if err != nil {
+						log.Error().Err(err).Msg("Error getting secret info")
+						s.components.toast.SetText("Error getting secret info")
+						return nil
+					}
+					cmd, err := editor.EditSettings(client.FormatSettings(selected.Title(), secretInfo.Settings()), selected)
+					if err != nil {
+						log.Error().Err(err).Msg("Error writing settings file")
+						s.components.toast.SetText("Failed to create temporary file")
+						return nil
+					}
+					return cmd
+				case "m":
+					if !s.masking.enabled {
+						s.components.toast.SetText("Values are not masked in this project")
+						return nil
+					}
+					s.masking.all = !s.masking.all
+					if s.masking.all {
+						s.components.toast.SetText("Secret revealed until another one is selected")
+					} else {
+						s.components.toast.SetText("Secret masked")
+					}
+					return s.showSecret()
+				case "M":
+					if !s.masking.enabled {
+						s.components.toast.SetText("Values are not masked in this project")
+						return nil
+					}
+					if !s.metadataOnly.showsValue() {
+						s.components.toast.SetText("Press o to fetch the value first")
+						return nil
+					}
+					keys := s.masking.keyNames()
+					if len(keys) == 0 {
+						s.components.toast.SetText("The secret has no keys, press m to reveal it")
+						return nil
+					}
+					s.Modal = view.NewRevealKeyForm(keys)
+					s.Modal.Init()
+				case "o":
+					if s.components.list.SelectedItem().FullPath() == "" {
+						s.components.toast.SetText("No secret selected")
+						return nil
+					}
+					if s.metadataOnly.showsValue() {
+						s.components.toast.SetText("The value is already shown")
+						return nil
+					}
+					s.metadataOnly.fetch = true
+					return s.showSecret()
+				case "O":
+					s.metadataOnly.enabled = !s.metadataOnly.enabled
+					s.metadataOnly.fetch = false
+					if s.metadataOnly.enabled {
+						s.components.toast.SetText("Browsing metadata only, o fetches a value")
+					} else {
+						s.components.toast.SetText("Browsing values")
+					}
+					return s.showSecret()
+				case "I":
+					s.openIam()
 				case "ctrl+f":
 					s.Modal = view.NewSearchForm()
 					s.Modal.Init()
+				case "F":
+					if s.gcp != nil {
+						s.Modal = view.NewFilterForm(s.gcp.Filter())
+						s.Modal.Init()
+					}
 				case "i":
 					selected := s.components.list.SelectedItem()
 					if selected.FullPath() != "" {
-						secretInfo, err := s.gcp.GetSecretInfo(selected.FullPath())
+						secretInfo, err := s.clientFor(selected).GetSecretInfo(selected.FullPath())
 						if err != nil {
 							log.Error().Err(err).Msg("Error getting secret info")
 							s.components.toast.SetText("Error getting secret info")
 						} else {
-							s.Modal = view.NewSecretInfoModal(secretInfo, selected)
+							modal := view.NewSecretInfoModal(secretInfo, selected)
+							versions, err := s.clientFor(selected).GetSecretVersions(selected.FullPath())
+							if err != nil {
+								log.Error().Err(err).Msg("Error getting secret versions")
+							} else {
+								modal.SetVersions(versions)
+							}
+							s.Modal = modal
 							s.Modal.Init()
 						}
 					} else {
@@ -326,16 +692,51 @@ func (s *Secrets) Update(msg tea.Msg) tea.Cmd {
 				log.Info().Msgf("Changes detected in secret %v", msg.CurrentSecret.Title())
 				s.components.toast.SetText("Changes detected")
 
-				s.Modal = view.NewConfirm("Do you want to create a new secret based on this?", msg)
+				name := msg.CurrentSecret.Title()
+				if msg.CurrentSecret.Type() == "version" {
+					name = msg.CurrentSecret.Related().Title()
+				}
+				s.Modal = s.confirmChange("Do you want to create a new secret based on this?", name, msg)
 				s.Modal.Init()
 
-				s.components.detail.SetContent(string(msg.SecretData))
+				s.components.detail.SetContent(string(s.masking.snapshot().apply(msg.SecretData)))
 				_, cmd = s.components.detail.Update(msg)
 				return cmd
 			}
 			return nil
+		case editor.SettingsEditedMsg:
+			if msg.Equal {
+				s.components.toast.SetText("No changes detected")
+				return nil
+			}
+			settings, err := client.ParseSettings(msg.Document)
+			if err != nil {
+				s.components.toast.SetText("Invalid settings: " + err.Error())
+				return nil
+			}
+			question := fmt.Sprintf("Update the settings of %s?", msg.CurrentSecret.Title())
+			s.Modal = s.confirmChange(question, msg.CurrentSecret.Title(), UpdateSettingsMsg{FullPath: msg.CurrentSecret.FullPath(), Settings: settings})
+			s.Modal.Init()
+			return nil
+		case editor.NewSecretEditedMsg:
+			if len(msg.SecretData) == 0 {
+				s.components.toast.SetText("Empty secret, nothing created")
+				return nil
+			}
+			question := fmt.Sprintf("Create secret %s?", msg.SecretName)
+			if !msg.Settings.ExpireTime.IsZero() {
+				question = fmt.Sprintf("Create secret %s expiring %s?", msg.SecretName, msg.Settings.ExpireTime.UTC().Format("2006-01-02 15:04 UTC"))
+			}
+			s.Modal = s.confirmChange(question, s.ProjectId, msg)
+			s.Modal.Init()
+			return nil
 		case SecretLoadedMsg:
+			s.refreshDenied(msg.Secret)
+			if secretKey(msg.Secret) == s.masking.secret {
+				s.masking.data = msg.Data
+			}
 			s.components.detail.SetContent(msg.Text)
+			s.components.detail.SetHighlightLines(msg.Highlights)
 			return nil
 		}
 	} else {
@@ -374,8 +775,10 @@ func (s *Secrets) Update(msg tea.Msg) tea.Cmd {
 }
 
 type SecretLoadedMsg struct {
-	Secret view.Secret
-	Text   string
+	Secret     view.Secret
+	Text       string
+	Data       []byte
+	Highlights []int
 }
 
 func adjustListWidth(s *Secrets, delta int) tea.Cmd {
@@ -391,11 +794,19 @@ func adjustListWidth(s *Secrets, delta int) tea.Cmd {
 	return resizeCmd
 }
 
+// newProjectSelector builds the project selector with a discoverer using the
+// credentials of the current project.
+func (s *Secrets) newProjectSelector() *view.ProjectSelector {
+	return view.NewProjectSelectorModal(client.NewProjectDiscoverer(config.GetTypeByProjectId(s.ProjectId), bootstrap.ClientOptions(s.ProjectId).Credentials))
+}
+
 func (s *Secrets) showSecret() tea.Cmd {
 	selected := s.components.list.SelectedItem()
+	project, projectResource, permissions := s.gcp, s.projectResource(), s.permissions
 
 	if selected.FullPath() == "" {
 		return func() tea.Msg {
+			permissions.load(project, projectResource, client.ProjectPermissions)
 			return SecretLoadedMsg{
 				Secret: selected,
 				Text:   "",
@@ -403,55 +814,163 @@ func (s *Secrets) showSecret() tea.Cmd {
 		}
 	}
 
+	gcp := s.clientFor(selected)
+	s.masking.follow(selected)
+	masking := s.masking.snapshot()
+	s.metadataOnly.follow(selected)
+	showsValue, metadata := s.metadataOnly.showsValue(), s.metadata
+	query := s.searchQuery()
 	return func() tea.Msg {
+		permissions.load(project, projectResource, client.ProjectPermissions)
+		permissions.load(gcp, selected.FullPath(), client.SecretPermissions)
+
+		if !showsValue {
+			entry, err := metadata.load(gcp, selected.FullPath())
+			if err != nil {
+				return SecretLoadedMsg{Secret: selected, Text: "Error loading secret metadata: " + err.Error()}
+			}
+			modal := view.NewSecretInfoModal(entry.info, selected)
+			modal.SetVersions(entry.versions)
+			return SecretLoadedMsg{Secret: selected, Text: modal.Summary("o")}
+		}
+
 		var text string
+		var data []byte
+		var highlights []int
 		text = "loading"
 		if selected.Type() == "version" {
-			versionSecret, err := s.gcp.GetSecretVersion(selected.FullPath(), strconv.Itoa(selected.Version()))
+			versionSecret, err := gcp.GetSecretVersion(selected.FullPath(), strconv.Itoa(selected.Version()))
 			if err != nil {
 				text = "Error loading secret version: " + err.Error()
 			} else {
-				text = ui.SyntaxHighlight(versionSecret)
+				data = versionSecret
+				shown := masking.apply(versionSecret)
+				text = ui.SyntaxHighlight(shown)
+				highlights = searchHighlights(query, versionSecret, shown)
 			}
 		} else {
-			secretData, err := s.gcp.GetSecret(selected.FullPath())
+			secretData, err := gcp.GetSecret(selected.FullPath())
 			if err != nil {
 				text = "Error loading secret: " + err.Error()
 			} else {
-				text = ui.SyntaxHighlight(secretData)
+				data = secretData
+				shown := masking.apply(secretData)
+				text = ui.SyntaxHighlight(shown)
+				highlights = searchHighlights(query, secretData, shown)
 			}
 		}
 		return SecretLoadedMsg{
-			Secret: selected,
-			Text:   text,
+			Secret:     selected,
+			Text:       text,
+			Data:       data,
+			Highlights: highlights,
 		}
 	}
 
 }
 
-func NewSecrets(gcp client.Client, selected int) *Secrets {
-	page := &Secrets{gcp: gcp, ListWidth: 31}
+func NewSecrets(gcp client.Client, projectId string, selected int, clipboard *clipboard.Clipboard) *Secrets {
+	page := &Secrets{gcp: gcp, ProjectId: projectId, ListWidth: 31, permissions: newPermissionCache(), metadata: newMetadataCache(), clipboard: clipboard}
 	page.Init()
 	page.Select(selected)
 	return page
 }
 
 func (s *Secrets) Init() {
+	s.resetSearch()
+	s.permissions.reset()
+	s.metadata.reset()
+	s.protection = config.GetProtectionByProjectId(s.ProjectId)
+	s.masking = masking{enabled: config.GetMaskByProjectId(s.ProjectId)}
+	s.metadataOnly = metadataOnly{enabled: config.GetMetadataOnlyByProjectId(s.ProjectId)}
+
 	secretList := view.NewSecretsList(50, 50, s.gcp)
+	secretList.SetExpiryWarning(time.Duration(config.GetExpiryWarningDays()) * 24 * time.Hour)
 	secretView := view.NewSecretView(50, 50)
 	help := view.NewHelp()
 	toast := view.NewToast()
+	project := config.GetProject(s.ProjectId)
+	banner := view.NewBanner(s.ProjectId, project.Name, project.Env, project.Color)
+	if s.gcp != nil {
+		banner.SetIdentity(s.gcp.Identity())
+	}
 
 	s.components = secretsComponents{
 		list:   &secretList,
 		detail: &secretView,
 		help:   &help,
 		toast:  &toast,
+		banner: &banner,
 	}
 
 	s.Update(s.showSecret()())
 }
 
+// Close stops any background work started by the page. A copied secret keeps
+// its countdown, so that switching project does not clear the clipboard.
+func (s *Secrets) Close() {
+	s.resetSearch()
+}
+
+// Quit closes the page and clears a copied secret that is still waiting to
+// be cleared, which would otherwise stay in the clipboard once smm quits.
+func (s *Secrets) Quit() {
+	s.Close()
+	if s.clipboard.Pending() {
+		if _, err := s.clipboard.Clear(); err != nil {
+			log.Error().Err(err).Msg("Error clearing the clipboard")
+		}
+	}
+}
+
+// Lock hides the detail pane, forgets what was revealed or fetched and
+// clears the clipboard if it still holds the secret copied last.
+func (s *Secrets) Lock() {
+	s.components.detail.SetContent("")
+	s.masking.all = false
+	s.masking.keys = nil
+	s.metadataOnly.fetch = false
+
+	if _, err := s.clipboard.Clear(); err != nil {
+		log.Error().Err(err).Msg("Error clearing the clipboard")
+	}
+}
+
+// applyFilter lists the project again with a new ListSecrets filter,
+// optionally saving it in the config.
+func (s *Secrets) applyFilter(filter string, save bool) {
+	s.cancelSearch()
+	if err := s.gcp.SetFilter(filter); err != nil {
+		log.Error().Err(err).Msg("Error applying filter")
+		s.components.toast.SetText("Invalid filter: " + err.Error())
+		return
+	}
+
+	s.components.list.LoadSecrets(s.gcp)
+	s.components.list.Select(0)
+
+	text := "Filter applied"
+	if filter == "" {
+		text = "Filter cleared"
+	}
+	if save {
+		config.SetFilterByProjectId(s.ProjectId, filter)
+		if err := config.Save(); err != nil {
+			log.Error().Err(err).Msg("Error saving filter")
+			s.components.toast.SetText("Error saving filter")
+			return
+		}
+		text += " and saved"
+	}
+	s.components.toast.SetText(text)
+}
+
+// SelectSecret selects the secret called name and shows its content.
+func (s *Secrets) SelectSecret(name string) {
+	s.components.list.SelectByName(name)
+	s.Update(s.showSecret()())
+}
+
 func (s *Secrets) Select(index int) {
 	s.components.list.Select(index)
 }
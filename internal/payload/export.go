@@ -0,0 +1,42 @@
+package payload
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var envNameInvalid = regexp.MustCompile(`[^A-Z0-9_]+`)
+
+// Export renders the entries of an env or JSON payload as shell export
+// lines, naming JSON leaves after their path, so db.host becomes DB_HOST.
+func Export(data []byte) (string, error) {
+	format, entries := Parse(data)
+	if format == FormatRaw {
+		return "", fmt.Errorf("only env and JSON secrets can be exported")
+	}
+
+	var lines strings.Builder
+	for _, entry := range entries {
+		name := entry.Key
+		if format == FormatJSON {
+			name = EnvName(entry.Key)
+		}
+		fmt.Fprintf(&lines, "export %s=%s\n", name, shellQuote(entry.Value))
+	}
+	return lines.String(), nil
+}
+
+// EnvName turns a JSON path into a variable name, e.g. "servers[0].host"
+// into SERVERS_0_HOST.
+func EnvName(key string) string {
+	name := strings.Trim(envNameInvalid.ReplaceAllString(strings.ToUpper(key), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,61 @@
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaskedValue is what a hidden value of n characters is shown as.
+func MaskedValue(n int) string {
+	return fmt.Sprintf("•••• (%d)", n)
+}
+
+// Mask hides the values of data whose key revealed does not accept, keeping
+// env keys and the JSON structure on the same lines. Any other payload is
+// hidden as a whole.
+func Mask(data []byte, revealed func(key string) bool) []byte {
+	if masked, ok := maskJSON(data, revealed); ok {
+		return masked
+	}
+	if masked, ok := maskEnv(string(data), revealed); ok {
+		return []byte(masked)
+	}
+	return []byte(MaskedValue(utf8.RuneCount(data)))
+}
+
+func maskJSON(data []byte, revealed func(key string) bool) ([]byte, bool) {
+	var masked []byte
+	last := 0
+	ok := walkJSON(data, func(key string, token json.Token, start, end int) {
+		if revealed(key) {
+			return
+		}
+		masked = append(masked, data[last:start]...)
+		masked = append(masked, fmt.Sprintf("%q", MaskedValue(utf8.RuneCountInString(scalarString(token))))...)
+		last = end
+	})
+	if !ok {
+		return nil, false
+	}
+	return append(masked, data[last:]...), true
+}
+
+func maskEnv(s string, revealed func(key string) bool) (string, bool) {
+	entries, ok := parseEnv(s)
+	if !ok {
+		return "", false
+	}
+
+	lines := strings.Split(s, "\n")
+	for _, entry := range entries {
+		if revealed(entry.Key) {
+			continue
+		}
+		line := lines[entry.Line]
+		match := envLineRegex.FindStringSubmatchIndex(line)
+		lines[entry.Line] = line[:match[4]] + MaskedValue(utf8.RuneCountInString(entry.Value))
+	}
+	return strings.Join(lines, "\n"), true
+}
@@ -0,0 +1,197 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatEnv  Format = "env"
+	FormatJSON Format = "json"
+	FormatRaw  Format = "raw"
+)
+
+// Entry is a single value of a structured payload: an env variable or a JSON
+// leaf addressed by its path, e.g. "database.hosts[0]".
+type Entry struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+var envLineRegex = regexp.MustCompile(`^\s*(?:export\s+)?([a-zA-Z_][a-zA-Z0-9_]*)\s*=\s*(.*)$`)
+
+// Parse detects whether data holds env variables or JSON and returns its
+// entries. Any other payload is reported as FormatRaw with no entries.
+func Parse(data []byte) (Format, []Entry) {
+	if entries, ok := parseJSON(data); ok {
+		return FormatJSON, entries
+	}
+	if entries, ok := parseEnv(string(data)); ok {
+		return FormatEnv, entries
+	}
+	return FormatRaw, nil
+}
+
+// LeafName returns the last segment of a key, so "database.host" and
+// "servers[0].host" both end in "host".
+func LeafName(key string) string {
+	if i := strings.LastIndexAny(key, ".]"); i >= 0 && i < len(key)-1 {
+		return key[i+1:]
+	}
+	return key
+}
+
+func parseEnv(s string) ([]Entry, bool) {
+	var entries []Entry
+	for i, line := range strings.Split(s, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		match := envLineRegex.FindStringSubmatch(line)
+		if match == nil {
+			return nil, false
+		}
+		entries = append(entries, Entry{Key: match[1], Value: unquote(strings.TrimSpace(match[2])), Line: i})
+	}
+
+	return entries, len(entries) > 0
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// jsonFrame tracks the container being decoded: the key or index of the next
+// value and whether an object is waiting for a key.
+type jsonFrame struct {
+	path     string
+	isArray  bool
+	index    int
+	key      string
+	needsKey bool
+}
+
+func parseJSON(data []byte) ([]Entry, bool) {
+	var entries []Entry
+	ok := walkJSON(data, func(key string, token json.Token, start, end int) {
+		line := bytes.Count(data[:end], []byte("\n"))
+		entries = append(entries, Entry{Key: key, Value: scalarString(token), Line: line})
+	})
+	if !ok {
+		return nil, false
+	}
+	return entries, true
+}
+
+// walkJSON calls leaf with the path of every scalar of a JSON object or array
+// and the offsets of its text in data. It reports whether data is such JSON.
+func walkJSON(data []byte, leaf func(key string, token json.Token, start, end int)) bool {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') || !json.Valid(trimmed) {
+		return false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var stack []*jsonFrame
+
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false
+		}
+
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if top != nil && !top.isArray && top.needsKey {
+			if key, ok := token.(string); ok {
+				top.key = key
+				top.needsKey = false
+				continue
+			}
+		}
+
+		switch token := token.(type) {
+		case json.Delim:
+			switch token {
+			case '{', '[':
+				stack = append(stack, &jsonFrame{path: childPath(top), isArray: token == '[', needsKey: token == '{'})
+			case '}', ']':
+				stack = stack[:len(stack)-1]
+				advance(stackTop(stack))
+			}
+		default:
+			end := int(decoder.InputOffset())
+			start := offset + len(data[offset:end]) - len(bytes.TrimLeft(data[offset:end], " \t\r\n:,"))
+			leaf(childPath(top), token, start, end)
+			advance(top)
+		}
+	}
+
+	return true
+}
+
+func stackTop(stack []*jsonFrame) *jsonFrame {
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1]
+}
+
+func childPath(frame *jsonFrame) string {
+	if frame == nil {
+		return ""
+	}
+	if frame.isArray {
+		return fmt.Sprintf("%s[%d]", frame.path, frame.index)
+	}
+	if frame.path == "" {
+		return frame.key
+	}
+	return frame.path + "." + frame.key
+}
+
+func advance(frame *jsonFrame) {
+	if frame == nil {
+		return
+	}
+	if frame.isArray {
+		frame.index++
+	} else {
+		frame.needsKey = true
+	}
+}
+
+func scalarString(token json.Token) string {
+	switch value := token.(type) {
+	case nil:
+		return "null"
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
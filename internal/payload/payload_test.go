@@ -0,0 +1,121 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PayloadTestSuite struct {
+	suite.Suite
+}
+
+func TestPayloadSuite(t *testing.T) {
+	suite.Run(t, new(PayloadTestSuite))
+}
+
+func (suite *PayloadTestSuite) TestParse_Env() {
+	t := suite.T()
+
+	format, entries := Parse([]byte("# comment\nexport KEY=\"value\"\n\nOTHER='x=y'"))
+
+	assert.Equal(t, FormatEnv, format)
+	assert.Equal(t, []Entry{
+		{Key: "KEY", Value: "value", Line: 1},
+		{Key: "OTHER", Value: "x=y", Line: 3},
+	}, entries)
+}
+
+func (suite *PayloadTestSuite) TestParse_JSON() {
+	t := suite.T()
+
+	format, entries := Parse([]byte("{\n  \"db\": {\"host\": \"h\", \"port\": 5432},\n  \"tags\": [\"a\", {\"x\": null}],\n  \"on\": true\n}"))
+
+	assert.Equal(t, FormatJSON, format)
+	assert.Equal(t, []Entry{
+		{Key: "db.host", Value: "h", Line: 1},
+		{Key: "db.port", Value: "5432", Line: 1},
+		{Key: "tags[0]", Value: "a", Line: 2},
+		{Key: "tags[1].x", Value: "null", Line: 2},
+		{Key: "on", Value: "true", Line: 3},
+	}, entries)
+}
+
+func (suite *PayloadTestSuite) TestParse_Raw() {
+	t := suite.T()
+
+	format, entries := Parse([]byte("-----BEGIN KEY-----\nabc"))
+
+	assert.Equal(t, FormatRaw, format)
+	assert.Empty(t, entries)
+}
+
+func (suite *PayloadTestSuite) TestLeafName() {
+	t := suite.T()
+
+	assert.Equal(t, "host", LeafName("db.host"))
+	assert.Equal(t, "host", LeafName("servers[0].host"))
+	assert.Equal(t, "servers[0]", LeafName("servers[0]"))
+	assert.Equal(t, "KEY", LeafName("KEY"))
+}
+
+func (suite *PayloadTestSuite) TestMask_Env() {
+	t := suite.T()
+
+	masked := Mask([]byte("# comment\nexport KEY=\"value\"\nOTHER=secret"), func(key string) bool { return key == "OTHER" })
+
+	assert.Equal(t, "# comment\nexport KEY=•••• (5)\nOTHER=secret", string(masked))
+}
+
+func (suite *PayloadTestSuite) TestMask_JSON() {
+	t := suite.T()
+
+	data := "{\n  \"db\": {\"host\": \"héllo\", \"port\": 5432},\n  \"tags\": [\"a\"]\n}"
+	masked := Mask([]byte(data), func(key string) bool { return key == "db.port" })
+
+	assert.Equal(t, "{\n  \"db\": {\"host\": \"•••• (5)\", \"port\": 5432},\n  \"tags\": [\"•••• (1)\"]\n}", string(masked))
+}
+
+func (suite *PayloadTestSuite) TestMask_Raw() {
+	t := suite.T()
+
+	masked := Mask([]byte("-----BEGIN KEY-----\nabc"), func(string) bool { return true })
+
+	assert.Equal(t, "•••• (23)", string(masked))
+}
+
+func (suite *PayloadTestSuite) TestExport_Env() {
+	t := suite.T()
+
+	lines, err := Export([]byte("KEY=value\nQUOTE=\"it's\""))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "export KEY='value'\nexport QUOTE='it'\\''s'\n", lines)
+}
+
+func (suite *PayloadTestSuite) TestExport_JSON() {
+	t := suite.T()
+
+	lines, err := Export([]byte(`{"db": {"host": "h"}, "servers": [{"port": 80}]}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "export DB_HOST='h'\nexport SERVERS_0_PORT='80'\n", lines)
+}
+
+func (suite *PayloadTestSuite) TestExport_Raw() {
+	t := suite.T()
+
+	_, err := Export([]byte("-----BEGIN KEY-----"))
+
+	assert.Error(t, err)
+}
+
+func (suite *PayloadTestSuite) TestEnvName() {
+	t := suite.T()
+
+	assert.Equal(t, "DB_HOST", EnvName("db.host"))
+	assert.Equal(t, "SERVERS_0_HOST", EnvName("servers[0].host"))
+	assert.Equal(t, "_1PASSWORD", EnvName("1password"))
+	assert.Equal(t, "API_KEY", EnvName("api-key"))
+}
@@ -0,0 +1,561 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"smm/internal/payload"
+)
+
+// Options change how the terms of a query are compared.
+type Options struct {
+	IgnoreCase bool
+}
+
+// Candidate is what a query is evaluated against: the metadata of a secret
+// and, when the query needs it, its payload.
+type Candidate struct {
+	Project    string
+	Labels     map[string]string
+	CreateTime time.Time
+	Payload    []byte
+}
+
+// Query is a parsed deep search expression.
+//
+// Terms are separated by spaces and joined with AND unless OR is written
+// between them; parentheses group terms. A term is one of:
+//
+//	text            payload contains text ("quoted text" for spaces)
+//	/regex/i        payload matches regex, the i flag ignores case
+//	key:NAME        an env key or JSON path (or its last segment) is NAME
+//	value:TEXT      an env value or JSON leaf contains TEXT
+//	label:k=v       the secret has label k with value v (label:k for any value)
+//	created:<30d    the secret is younger than 30d (>30d for older)
+//	created:>2024-01-31  the secret was created after a date (< for before)
+//	project:a,b     the secret belongs to one of the projects
+//
+// key: and value: also accept a /regex/.
+type Query struct {
+	raw      string
+	root     node
+	projects []string
+}
+
+type node interface {
+	match(c *candidate) bool
+	needsPayload() bool
+	highlight(c *candidate, lines map[int]bool)
+}
+
+// candidate caches the parsed entries of the payload across terms.
+type candidate struct {
+	Candidate
+	parsed  bool
+	entries []payload.Entry
+}
+
+func (c *candidate) payloadEntries() []payload.Entry {
+	if !c.parsed {
+		_, c.entries = payload.Parse(c.Payload)
+		c.parsed = true
+	}
+	return c.entries
+}
+
+// Parse compiles input into a Query.
+func Parse(input string, opts Options) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+
+	p := &parser{tokens: tokens, opts: opts, now: time.Now()}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+
+	return &Query{raw: input, root: root, projects: p.projects}, nil
+}
+
+func (q *Query) String() string {
+	return q.raw
+}
+
+// NeedsPayload reports whether the query looks at secret values. Queries made
+// only of label: and created: filters can be answered from metadata.
+func (q *Query) NeedsPayload() bool {
+	return q.root.needsPayload()
+}
+
+// Projects returns the projects named in project: terms, which a search must
+// cover in addition to the current one.
+func (q *Query) Projects() []string {
+	return q.projects
+}
+
+func (q *Query) Match(c Candidate) bool {
+	return q.root.match(&candidate{Candidate: c})
+}
+
+// Highlights returns the sorted line numbers of data matched by the payload
+// terms of the query.
+func (q *Query) Highlights(data []byte) []int {
+	lines := map[int]bool{}
+	q.root.highlight(&candidate{Candidate: Candidate{Payload: data}}, lines)
+
+	result := make([]int, 0, len(lines))
+	for line := range lines {
+		result = append(result, line)
+	}
+	sort.Ints(result)
+	return result
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(c *candidate) bool { return n.left.match(c) && n.right.match(c) }
+func (n andNode) needsPayload() bool      { return n.left.needsPayload() || n.right.needsPayload() }
+func (n andNode) highlight(c *candidate, lines map[int]bool) {
+	n.left.highlight(c, lines)
+	n.right.highlight(c, lines)
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(c *candidate) bool { return n.left.match(c) || n.right.match(c) }
+func (n orNode) needsPayload() bool      { return n.left.needsPayload() || n.right.needsPayload() }
+func (n orNode) highlight(c *candidate, lines map[int]bool) {
+	n.left.highlight(c, lines)
+	n.right.highlight(c, lines)
+}
+
+// matcher compares a single string, either by substring or by regex.
+type matcher struct {
+	text  string
+	regex *regexp.Regexp
+	fold  bool
+}
+
+func (m matcher) contains(s string) bool {
+	if m.regex != nil {
+		return m.regex.MatchString(s)
+	}
+	if m.fold {
+		return strings.Contains(strings.ToLower(s), m.text)
+	}
+	return strings.Contains(s, m.text)
+}
+
+func (m matcher) equals(s string) bool {
+	if m.regex != nil {
+		return m.regex.MatchString(s)
+	}
+	if m.fold {
+		return strings.EqualFold(s, m.text)
+	}
+	return s == m.text
+}
+
+type textNode struct{ m matcher }
+
+func (n textNode) match(c *candidate) bool { return n.m.contains(string(c.Payload)) }
+func (n textNode) needsPayload() bool      { return true }
+func (n textNode) highlight(c *candidate, lines map[int]bool) {
+	for i, line := range strings.Split(string(c.Payload), "\n") {
+		if n.m.contains(line) {
+			lines[i] = true
+		}
+	}
+}
+
+type keyNode struct{ m matcher }
+
+func (n keyNode) matchEntry(entry payload.Entry) bool {
+	return n.m.equals(entry.Key) || n.m.equals(payload.LeafName(entry.Key))
+}
+
+func (n keyNode) match(c *candidate) bool {
+	for _, entry := range c.payloadEntries() {
+		if n.matchEntry(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n keyNode) needsPayload() bool { return true }
+
+func (n keyNode) highlight(c *candidate, lines map[int]bool) {
+	for _, entry := range c.payloadEntries() {
+		if n.matchEntry(entry) {
+			lines[entry.Line] = true
+		}
+	}
+}
+
+type valueNode struct{ m matcher }
+
+func (n valueNode) match(c *candidate) bool {
+	entries := c.payloadEntries()
+	if entries == nil {
+		return n.m.contains(string(c.Payload))
+	}
+	for _, entry := range entries {
+		if n.m.contains(entry.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n valueNode) needsPayload() bool { return true }
+
+func (n valueNode) highlight(c *candidate, lines map[int]bool) {
+	entries := c.payloadEntries()
+	if entries == nil {
+		textNode(n).highlight(c, lines)
+		return
+	}
+	for _, entry := range entries {
+		if n.m.contains(entry.Value) {
+			lines[entry.Line] = true
+		}
+	}
+}
+
+type labelNode struct {
+	key      string
+	value    string
+	anyValue bool
+}
+
+func (n labelNode) match(c *candidate) bool {
+	value, ok := c.Labels[n.key]
+	return ok && (n.anyValue || value == n.value)
+}
+
+func (n labelNode) needsPayload() bool                 { return false }
+func (n labelNode) highlight(*candidate, map[int]bool) {}
+
+type projectNode struct{ projects []string }
+
+func (n projectNode) match(c *candidate) bool {
+	for _, project := range n.projects {
+		if project == c.Project {
+			return true
+		}
+	}
+	return false
+}
+
+func (n projectNode) needsPayload() bool                 { return false }
+func (n projectNode) highlight(*candidate, map[int]bool) {}
+
+type createdNode struct {
+	before bool
+	cutoff time.Time
+}
+
+func (n createdNode) match(c *candidate) bool {
+	if n.before {
+		return c.CreateTime.Before(n.cutoff)
+	}
+	return c.CreateTime.After(n.cutoff)
+}
+
+func (n createdNode) needsPayload() bool                 { return false }
+func (n createdNode) highlight(*candidate, map[int]bool) {}
+
+// token is a word of the query. quoted is set when the word starts with a
+// quote, so that "OR" or "key:x" can be searched for literally.
+type token struct {
+	text   string
+	quoted bool
+}
+
+// tokenize splits input on spaces, keeping quoted strings, regexes and
+// parentheses as single tokens.
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, token{text: string(r)})
+			i++
+		default:
+			var b strings.Builder
+			quoted := false
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				switch runes[i] {
+				case '"':
+					end := indexRune(runes, i+1, '"')
+					if end < 0 {
+						return nil, fmt.Errorf("unterminated quote")
+					}
+					quoted = quoted || b.Len() == 0
+					b.WriteString(string(runes[i+1 : end]))
+					i = end + 1
+				case '/':
+					if b.Len() > 0 && !strings.HasSuffix(b.String(), ":") {
+						b.WriteRune('/')
+						i++
+						continue
+					}
+					end := indexRegexEnd(runes, i+1)
+					if end < 0 {
+						return nil, fmt.Errorf("unterminated regex")
+					}
+					b.WriteString(string(runes[i : end+1]))
+					i = end + 1
+				default:
+					b.WriteRune(runes[i])
+					i++
+				}
+			}
+			tokens = append(tokens, token{text: b.String(), quoted: quoted})
+		}
+	}
+
+	return tokens, nil
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexRegexEnd(runes []rune, from int) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == '\\' {
+			i++
+			continue
+		}
+		if runes[i] == '/' {
+			return i
+		}
+	}
+	return -1
+}
+
+type parser struct {
+	tokens   []token
+	pos      int
+	opts     Options
+	now      time.Time
+	projects []string
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) isKeyword(word string) bool {
+	t, ok := p.peek()
+	return ok && !t.quoted && t.text == word
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if p.isKeyword("AND") {
+			p.pos++
+		} else if t, ok := p.peek(); !ok || p.isKeyword("OR") || (t.text == ")" && !t.quoted) {
+			return left, nil
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *parser) parseTerm() (node, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("missing term at end of query")
+	}
+	p.pos++
+
+	if !t.quoted {
+		switch t.text {
+		case "(":
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.isKeyword(")") {
+				return nil, fmt.Errorf("missing closing parenthesis")
+			}
+			p.pos++
+			return inner, nil
+		case ")", "AND", "OR":
+			return nil, fmt.Errorf("unexpected %q", t.text)
+		}
+	}
+
+	if !t.quoted {
+		if field, value, found := strings.Cut(t.text, ":"); found {
+			switch field {
+			case "key":
+				m, err := p.matcher(value)
+				return keyNode{m}, err
+			case "value":
+				m, err := p.matcher(value)
+				return valueNode{m}, err
+			case "label":
+				return parseLabel(value)
+			case "created":
+				return p.parseCreated(value)
+			case "project":
+				return p.parseProject(value)
+			}
+		}
+	}
+
+	if t.quoted {
+		return textNode{p.textMatcher(t.text)}, nil
+	}
+	m, err := p.matcher(t.text)
+	return textNode{m}, err
+}
+
+func (p *parser) textMatcher(text string) matcher {
+	if p.opts.IgnoreCase {
+		return matcher{text: strings.ToLower(text), fold: true}
+	}
+	return matcher{text: text}
+}
+
+func (p *parser) matcher(text string) (matcher, error) {
+	if text == "" {
+		return matcher{}, fmt.Errorf("missing value in term")
+	}
+	if len(text) < 2 || text[0] != '/' {
+		return p.textMatcher(text), nil
+	}
+
+	end := strings.LastIndex(text, "/")
+	if end == 0 {
+		return p.textMatcher(text), nil
+	}
+	expr, flags := text[1:end], text[end+1:]
+	switch {
+	case flags == "i" || (flags == "" && p.opts.IgnoreCase):
+		expr = "(?i)" + expr
+	case flags != "":
+		return matcher{}, fmt.Errorf("unknown regex flags %q", flags)
+	}
+
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return matcher{}, fmt.Errorf("invalid regex %q: %w", text, err)
+	}
+	return matcher{regex: regex}, nil
+}
+
+func parseLabel(value string) (node, error) {
+	key, labelValue, hasValue := strings.Cut(value, "=")
+	if key == "" {
+		return nil, fmt.Errorf("missing label name in label:%s", value)
+	}
+	return labelNode{key: key, value: labelValue, anyValue: !hasValue}, nil
+}
+
+func (p *parser) parseProject(value string) (node, error) {
+	var projects []string
+	for _, project := range strings.Split(value, ",") {
+		if project != "" {
+			projects = append(projects, project)
+		}
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("missing project in project:%s", value)
+	}
+
+	p.projects = append(p.projects, projects...)
+	return projectNode{projects}, nil
+}
+
+func (p *parser) parseCreated(value string) (node, error) {
+	if len(value) < 2 || (value[0] != '<' && value[0] != '>') {
+		return nil, fmt.Errorf("created: expects <AGE, >AGE, <DATE or >DATE")
+	}
+	younger := value[0] == '<'
+	operand := value[1:]
+
+	if date, err := time.Parse("2006-01-02", operand); err == nil {
+		return createdNode{before: younger, cutoff: date}, nil
+	}
+
+	age, err := parseAge(operand)
+	if err != nil {
+		return nil, err
+	}
+	// A younger secret was created after the cutoff, an older one before it.
+	return createdNode{before: !younger, cutoff: p.now.Add(-age)}, nil
+}
+
+// parseAge reads durations such as 90m, 12h, 30d or 2w.
+func parseAge(value string) (time.Duration, error) {
+	units := map[byte]time.Duration{
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}
+
+	unit, ok := units[value[len(value)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid age %q, use m, h, d or w", value)
+	}
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	return time.Duration(amount) * unit, nil
+}
@@ -0,0 +1,137 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type QueryTestSuite struct {
+	suite.Suite
+	env  Candidate
+	json Candidate
+}
+
+func (suite *QueryTestSuite) SetupTest() {
+	suite.env = Candidate{
+		Project:    "dev",
+		Labels:     map[string]string{"team": "payments"},
+		CreateTime: time.Now().Add(-48 * time.Hour),
+		Payload:    []byte("DB_HOST=localhost\nREDIS_URL=\"redis://cache:6379\"\nDEBUG=false"),
+	}
+	suite.json = Candidate{
+		Project:    "prod",
+		Labels:     map[string]string{"team": "search"},
+		CreateTime: time.Now().Add(-90 * 24 * time.Hour),
+		Payload:    []byte("{\n  \"database\": {\n    \"host\": \"db.internal\",\n    \"password\": \"Secret\"\n  },\n  \"servers\": [\"a\", \"b\"]\n}"),
+	}
+}
+
+func TestQuerySuite(t *testing.T) {
+	suite.Run(t, new(QueryTestSuite))
+}
+
+func (suite *QueryTestSuite) match(input string, opts Options, candidate Candidate) bool {
+	query, err := Parse(input, opts)
+	suite.Require().NoError(err)
+	return query.Match(candidate)
+}
+
+func (suite *QueryTestSuite) TestPlainText() {
+	t := suite.T()
+
+	assert.True(t, suite.match("localhost", Options{}, suite.env))
+	assert.False(t, suite.match("LOCALHOST", Options{}, suite.env))
+	assert.True(t, suite.match("LOCALHOST", Options{IgnoreCase: true}, suite.env))
+	assert.True(t, suite.match(`"redis://cache"`, Options{}, suite.env))
+}
+
+func (suite *QueryTestSuite) TestRegex() {
+	t := suite.T()
+
+	assert.True(t, suite.match(`/cache:\d+/`, Options{}, suite.env))
+	assert.False(t, suite.match(`/SECRET/`, Options{}, suite.json))
+	assert.True(t, suite.match(`/SECRET/i`, Options{}, suite.json))
+}
+
+func (suite *QueryTestSuite) TestKey() {
+	t := suite.T()
+
+	assert.True(t, suite.match("key:REDIS_URL", Options{}, suite.env))
+	assert.False(t, suite.match("key:REDIS", Options{}, suite.env))
+	assert.False(t, suite.match("key:REDIS_URL", Options{}, suite.json))
+	assert.True(t, suite.match("key:database.host", Options{}, suite.json))
+	assert.True(t, suite.match("key:password", Options{}, suite.json))
+	assert.True(t, suite.match("key:/^servers\\[1\\]$/", Options{}, suite.json))
+}
+
+func (suite *QueryTestSuite) TestValue() {
+	t := suite.T()
+
+	assert.True(t, suite.match("value:redis://", Options{}, suite.env))
+	assert.False(t, suite.match("value:REDIS_URL", Options{}, suite.env))
+	assert.True(t, suite.match("value:db.internal", Options{}, suite.json))
+}
+
+func (suite *QueryTestSuite) TestLabelAndCreated() {
+	t := suite.T()
+
+	assert.True(t, suite.match("label:team=payments", Options{}, suite.env))
+	assert.False(t, suite.match("label:team=payments", Options{}, suite.json))
+	assert.True(t, suite.match("label:team", Options{}, suite.json))
+	assert.True(t, suite.match("created:<30d", Options{}, suite.env))
+	assert.False(t, suite.match("created:<30d", Options{}, suite.json))
+	assert.True(t, suite.match("created:>30d", Options{}, suite.json))
+	assert.True(t, suite.match("created:>2000-01-01", Options{}, suite.json))
+}
+
+func (suite *QueryTestSuite) TestProject() {
+	t := suite.T()
+
+	assert.True(t, suite.match("project:dev,staging", Options{}, suite.env))
+	assert.False(t, suite.match("project:dev,staging", Options{}, suite.json))
+
+	query, _ := Parse("project:dev,staging OR project:prod key:x", Options{})
+	assert.Equal(t, []string{"dev", "staging", "prod"}, query.Projects())
+}
+
+func (suite *QueryTestSuite) TestBooleanOperators() {
+	t := suite.T()
+
+	assert.True(t, suite.match("key:DB_HOST key:DEBUG", Options{}, suite.env))
+	assert.False(t, suite.match("key:DB_HOST AND key:password", Options{}, suite.env))
+	assert.True(t, suite.match("key:password OR key:DB_HOST", Options{}, suite.env))
+	assert.True(t, suite.match("label:team=search (key:nope OR key:host)", Options{}, suite.json))
+	assert.False(t, suite.match("label:team=payments (key:nope OR key:host)", Options{}, suite.json))
+}
+
+func (suite *QueryTestSuite) TestNeedsPayload() {
+	t := suite.T()
+
+	query, _ := Parse("label:team=payments created:<30d", Options{})
+	assert.False(t, query.NeedsPayload())
+
+	query, _ = Parse("label:team=payments key:DB_HOST", Options{})
+	assert.True(t, query.NeedsPayload())
+}
+
+func (suite *QueryTestSuite) TestHighlights() {
+	t := suite.T()
+
+	query, _ := Parse("key:REDIS_URL OR false", Options{})
+	assert.Equal(t, []int{1, 2}, query.Highlights(suite.env.Payload))
+
+	query, _ = Parse("key:password", Options{})
+	assert.Equal(t, []int{3}, query.Highlights(suite.json.Payload))
+}
+
+func (suite *QueryTestSuite) TestParseErrors() {
+	t := suite.T()
+
+	for _, input := range []string{"", "(key:a", "key:a OR", `"open`, "/open", "created:soon", "created:<3y", "label:=x", "project:,", "/a/x"} {
+		_, err := Parse(input, Options{})
+		assert.Error(t, err, input)
+	}
+}
@@ -11,23 +11,56 @@ import (
 	"unicode/utf8"
 )
 
+// wrapWidth is the column SyntaxHighlight wraps long lines at.
+const wrapWidth = 255
+
 func SyntaxHighlight(secretData []byte) string {
 	if !isPrintable(secretData) {
 		return "\033[37mNon printable data.\033[0m"
 	}
 
 	var buf bytes.Buffer
-	format := detectFormat(secretData)
+	format := DetectFormat(secretData)
 	err := quick.Highlight(&buf, string(secretData), format, "terminal", "rrt")
 	if err != nil {
 		panic(err)
 	}
 
-	text := wordwrap.String(buf.String(), 255)
+	text := wordwrap.String(buf.String(), wrapWidth)
 	return text
 }
 
-func detectFormat(secretData []byte) string {
+// WrappedLines maps line numbers of secretData to the lines SyntaxHighlight
+// renders them on, which differ once a long line has been wrapped.
+func WrappedLines(secretData []byte, lines []int) []int {
+	if len(lines) == 0 || !isPrintable(secretData) {
+		return nil
+	}
+
+	var starts, counts []int
+	wrapped := 0
+	for _, line := range strings.Split(string(secretData), "\n") {
+		count := strings.Count(wordwrap.String(line, wrapWidth), "\n") + 1
+		starts = append(starts, wrapped)
+		counts = append(counts, count)
+		wrapped += count
+	}
+
+	var result []int
+	for _, line := range lines {
+		if line < 0 || line >= len(starts) {
+			continue
+		}
+		for i := 0; i < counts[line]; i++ {
+			result = append(result, starts[line]+i)
+		}
+	}
+	return result
+}
+
+// DetectFormat returns the chroma lexer matching the payload: bash for env
+// variables, json, php, ini or default.
+func DetectFormat(secretData []byte) string {
 	if isEnv(string(secretData)) {
 		return "bash"
 	}
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -39,7 +40,7 @@ func (suite *FormatTestSuite) TestDetectFormat_EnvFormat() {
 	t := suite.T()
 	
 	envData := []byte("KEY=value\nANOTHER_KEY=another_value")
-	format := detectFormat(envData)
+	format := DetectFormat(envData)
 	
 	assert.Equal(t, "bash", format)
 }
@@ -48,7 +49,7 @@ func (suite *FormatTestSuite) TestDetectFormat_JSONFormat() {
 	t := suite.T()
 	
 	jsonData := []byte(`{"key": "value", "another_key": "another_value"}`)
-	format := detectFormat(jsonData)
+	format := DetectFormat(jsonData)
 	
 	assert.Equal(t, "json", format)
 }
@@ -57,7 +58,7 @@ func (suite *FormatTestSuite) TestDetectFormat_PHPFormat() {
 	t := suite.T()
 	
 	phpData := []byte("<?php\n$config = array();")
-	format := detectFormat(phpData)
+	format := DetectFormat(phpData)
 	
 	assert.Equal(t, "php", format)
 }
@@ -66,7 +67,7 @@ func (suite *FormatTestSuite) TestDetectFormat_INIFormat() {
 	t := suite.T()
 	
 	iniData := []byte("[section]\nkey=value\nanother_key=another_value")
-	format := detectFormat(iniData)
+	format := DetectFormat(iniData)
 	
 	assert.Equal(t, "ini", format)
 }
@@ -75,7 +76,7 @@ func (suite *FormatTestSuite) TestDetectFormat_DefaultFormat() {
 	t := suite.T()
 	
 	plainData := []byte("This is just plain text")
-	format := detectFormat(plainData)
+	format := DetectFormat(plainData)
 	
 	assert.Equal(t, "default", format)
 }
@@ -264,12 +265,33 @@ func (suite *FormatTestSuite) TestSyntaxHighlight_WordWrapping() {
 	assert.Contains(t, result, "very_long_value")
 }
 
+func (suite *FormatTestSuite) TestWrappedLines_FollowsWrapping() {
+	t := suite.T()
+
+	long := "LONG=" + strings.Repeat("word ", 80)
+	testData := []byte("FIRST=1\n" + long + "\nLAST=3")
+	rendered := strings.Split(ansi.Strip(SyntaxHighlight(testData)), "\n")
+
+	assert.Equal(t, []int{0}, WrappedLines(testData, []int{0}))
+
+	longLines := WrappedLines(testData, []int{1})
+	assert.Greater(t, len(longLines), 1, "the long line is wrapped")
+	assert.Contains(t, rendered[longLines[0]], "LONG=")
+
+	last := WrappedLines(testData, []int{2})
+	assert.Equal(t, []int{longLines[len(longLines)-1] + 1}, last)
+	assert.Contains(t, rendered[last[0]], "LAST=3")
+
+	assert.Empty(t, WrappedLines(testData, []int{7}))
+	assert.Empty(t, WrappedLines([]byte{0x00, 0x01}, []int{0}))
+}
+
 func (suite *FormatTestSuite) TestDetectFormat_PriorityOrder() {
 	t := suite.T()
 	
 	// Test that ENV format takes precedence over other formats when ambiguous
 	ambiguousData := []byte("KEY=value")
-	format := detectFormat(ambiguousData)
+	format := DetectFormat(ambiguousData)
 	
 	assert.Equal(t, "bash", format, "ENV format should be detected first")
 }
\ No newline at end of file
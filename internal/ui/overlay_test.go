@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -129,6 +130,20 @@ func (suite *OverlayTestSuite) TestPlaceOverlay_ForegroundLargerThanBackground()
 	assert.Contains(t, result, "Very long foreground")
 }
 
+func (suite *OverlayTestSuite) TestPlaceOverlay_WideAndLinkedForeground() {
+	t := suite.T()
+
+	fg := Hyperlink("https://example.com", "日本")
+	bg := "0123456789\n" + Hyperlink("https://example.com", "0123456789")
+
+	result := PlaceOverlay(2, 1, fg, bg, false)
+	lines := strings.Split(result, "\n")
+
+	assert.Equal(t, "0123456789", lines[0])
+	assert.Equal(t, 10, lipgloss.Width(lines[1]))
+	assert.Contains(t, lines[1], "6789")
+}
+
 func (suite *OverlayTestSuite) TestCutLeft_BasicFunctionality() {
 	t := suite.T()
 	
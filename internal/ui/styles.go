@@ -1,6 +1,11 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
 
 func StyleLow() lipgloss.Style {
 	return lipgloss.NewStyle().Foreground(lipgloss.Color("#5a5a5a"))
@@ -36,6 +41,47 @@ func StyleBorder(selected bool) lipgloss.Style {
 		BorderForeground(lipgloss.Color(color))
 }
 
+// accentColors are the colour names accepted for a project accent.
+var accentColors = map[string]string{
+	"red":    "#E06C75",
+	"orange": "#D19A66",
+	"yellow": "#E5C07B",
+	"green":  "#98C379",
+	"blue":   "#61AFEF",
+	"purple": "#C678DD",
+	"cyan":   "#56B6C2",
+}
+
+// AccentColor resolves a project accent, either a colour name or anything
+// lipgloss understands such as "#FF0000" or an ANSI number.
+func AccentColor(accent string) lipgloss.Color {
+	if color, ok := accentColors[strings.ToLower(accent)]; ok {
+		return lipgloss.Color(color)
+	}
+	return lipgloss.Color(accent)
+}
+
+// StyleAccentBorder is StyleBorder drawn in the project accent while
+// selected, so that the active panel always shows it.
+func StyleAccentBorder(selected bool, accent string) lipgloss.Style {
+	if accent == "" || !selected {
+		return StyleBorder(selected)
+	}
+	return StyleBorder(selected).BorderForeground(AccentColor(accent))
+}
+
+// StyleBanner is the project banner, filled with the accent when the
+// project has one.
+func StyleBanner(accent string) lipgloss.Style {
+	style := lipgloss.NewStyle().
+		Bold(true).
+		Padding(0, 1)
+	if accent == "" {
+		return style.Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#303030"))
+	}
+	return style.Foreground(lipgloss.Color("#000000")).Background(AccentColor(accent))
+}
+
 func StyleModal() lipgloss.Style {
 	return lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#FFFFFF")).
@@ -61,3 +107,29 @@ func StyleToast() lipgloss.Style {
 		Foreground(lipgloss.Color("#FFFF00")).
 		Align(lipgloss.Center)
 }
+
+func StyleTag() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#E5C07B"))
+}
+
+func StyleBadge() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#61AFEF"))
+}
+
+func StyleAdded() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#98C379"))
+}
+
+func StyleWarning() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF6B6B"))
+}
+
+// Hyperlink renders text as an OSC 8 link to url. Terminals without
+// hyperlink support show the text alone.
+func Hyperlink(url, text string) string {
+	return ansi.SetHyperlink(url) + text + ansi.ResetHyperlink()
+}
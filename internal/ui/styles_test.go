@@ -237,4 +237,29 @@ func (suite *StylesTestSuite) TestStyleBorderToggle() {
 	assert.NotEmpty(t, unselectedResult)
 	assert.Contains(t, selectedResult, content)
 	assert.Contains(t, unselectedResult, content)
-}
\ No newline at end of file
+}
+
+func (suite *StylesTestSuite) TestHyperlink() {
+	t := suite.T()
+
+	link := Hyperlink("https://example.com", "console")
+
+	assert.Equal(t, "\x1b]8;;https://example.com\x07console\x1b]8;;\x07", link)
+	assert.Equal(t, len("console"), lipgloss.Width(link))
+}
+
+func (suite *StylesTestSuite) TestAccentColor() {
+	t := suite.T()
+
+	assert.Equal(t, lipgloss.Color("#E06C75"), AccentColor("Red"))
+	assert.Equal(t, lipgloss.Color("#123456"), AccentColor("#123456"))
+	assert.Equal(t, lipgloss.Color("9"), AccentColor("9"))
+}
+
+func (suite *StylesTestSuite) TestStyleAccentBorder() {
+	t := suite.T()
+
+	assert.Equal(t, AccentColor("red"), StyleAccentBorder(true, "red").GetBorderTopForeground())
+	assert.Equal(t, StyleBorder(false).GetBorderTopForeground(), StyleAccentBorder(false, "red").GetBorderTopForeground())
+	assert.Equal(t, StyleBorder(true).GetBorderTopForeground(), StyleAccentBorder(true, "").GetBorderTopForeground())
+}
@@ -0,0 +1,92 @@
+package view
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rs/zerolog/log"
+	"smm/internal/client"
+	"smm/internal/ui"
+)
+
+// AliasMessage asks to show the version an alias points to or, when Assign
+// is set, to point the alias to the selected version.
+type AliasMessage struct {
+	Alias  string
+	Assign bool
+}
+
+type AliasForm struct {
+	teaView    textinput.Model
+	assign     bool
+	alertText  string
+	alertStyle lipgloss.Style
+}
+
+// NewAliasForm asks for an alias to open or, with assign, for the alias to
+// point to the version described by target.
+func NewAliasForm(target string, assign bool) *AliasForm {
+	form := textinput.New()
+	form.Prompt = "Open alias: "
+	if assign {
+		form.Prompt = "Alias for " + target + ": "
+	}
+	form.ShowSuggestions = false
+	form.Placeholder = "prod"
+	form.Focus()
+	form.CharLimit = 63
+	form.Width = 32
+
+	alertStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF6B6B")).
+		Bold(true)
+
+	return &AliasForm{teaView: form, assign: assign, alertStyle: alertStyle}
+}
+
+func (p *AliasForm) Init() tea.Cmd {
+	return nil
+}
+
+func (p *AliasForm) Value() string {
+	return p.teaView.Value()
+}
+
+func (p *AliasForm) Update(msg tea.Msg) (Modal, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEnter:
+			alias := p.teaView.Value()
+			log.Info().Msgf("AliasForm: %v (assign: %v)", alias, p.assign)
+			if err := client.ValidateAlias(alias); err != nil {
+				p.alertText = err.Error()
+				return p, nil
+			}
+			assign := p.assign
+			return p, func() tea.Msg {
+				return AliasMessage{Alias: alias, Assign: assign}
+			}
+		default:
+			p.alertText = ""
+		}
+	}
+
+	p.teaView, cmd = p.teaView.Update(msg)
+	return p, cmd
+}
+
+func (p *AliasForm) View() string {
+	help := "enter: open the version the alias points to"
+	if p.assign {
+		help = "enter: point the alias to this version, moving it if needed"
+	}
+
+	view := lipgloss.JoinVertical(lipgloss.Left, p.teaView.View(), ui.StyleLow().Render(help))
+	if p.alertText != "" {
+		view = lipgloss.JoinVertical(lipgloss.Left, view, p.alertStyle.Render(p.alertText))
+	}
+
+	return lipgloss.NewStyle().Width(64).Render(view)
+}
@@ -0,0 +1,53 @@
+package view
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AliasFormTestSuite struct {
+	suite.Suite
+}
+
+func TestAliasFormSuite(t *testing.T) {
+	suite.Run(t, new(AliasFormTestSuite))
+}
+
+func (suite *AliasFormTestSuite) TestOpen() {
+	t := suite.T()
+	form := NewAliasForm("latest version", false)
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("prod")})
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, AliasMessage{Alias: "prod"}, cmd())
+	assert.Contains(t, form.View(), "Open alias")
+}
+
+func (suite *AliasFormTestSuite) TestAssign() {
+	t := suite.T()
+	form := NewAliasForm("version 3", true)
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("canary")})
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, AliasMessage{Alias: "canary", Assign: true}, cmd())
+	assert.Contains(t, form.View(), "version 3")
+}
+
+func (suite *AliasFormTestSuite) TestInvalidAlias() {
+	t := suite.T()
+	form := NewAliasForm("latest version", true)
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("latest")})
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Nil(t, cmd)
+	assert.Contains(t, form.View(), "reserved")
+
+	form.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.NotContains(t, form.View(), "reserved")
+}
@@ -0,0 +1,57 @@
+package view
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"smm/internal/ui"
+)
+
+// Banner names the active project above the panels, in the project accent,
+// so that it is always clear which environment is being changed.
+type Banner struct {
+	projectId string
+	name      string
+	env       string
+	accent    string
+	identity  string
+	width     int
+}
+
+func NewBanner(projectId, name, env, accent string) Banner {
+	return Banner{projectId: projectId, name: name, env: env, accent: accent}
+}
+
+func (b *Banner) SetWidth(width int) {
+	b.width = width
+}
+
+// SetIdentity shows the account the project is accessed as.
+func (b *Banner) SetIdentity(identity string) {
+	b.identity = identity
+}
+
+// Accent returns the colour of the project, empty when it has none.
+func (b *Banner) Accent() string {
+	return b.accent
+}
+
+func (b *Banner) View() string {
+	label := b.projectId
+	if b.name != "" {
+		label = b.name + " · " + b.projectId
+	}
+	right := strings.ToUpper(b.env)
+	if b.identity != "" {
+		right = strings.TrimSpace("as " + b.identity + "  " + right)
+	}
+
+	gap := b.width - 2 - lipgloss.Width(label) - lipgloss.Width(right)
+	if gap < 1 {
+		gap = 1
+	}
+	return ui.StyleBanner(b.accent).
+		Width(max(b.width, 0)).
+		MaxHeight(1).
+		Render(label + strings.Repeat(" ", gap) + right)
+}
@@ -0,0 +1,55 @@
+package view
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BannerTestSuite struct {
+	suite.Suite
+}
+
+func TestBannerSuite(t *testing.T) {
+	suite.Run(t, new(BannerTestSuite))
+}
+
+func (suite *BannerTestSuite) TestView() {
+	t := suite.T()
+	banner := NewBanner("payments-prod", "Payments", "prod", "red")
+	banner.SetWidth(60)
+
+	view := banner.View()
+
+	assert.Contains(t, view, "Payments · payments-prod")
+	assert.Contains(t, view, "PROD")
+	assert.Equal(t, 60, lipgloss.Width(view))
+	assert.Equal(t, 1, lipgloss.Height(view))
+	assert.Equal(t, "red", banner.Accent())
+}
+
+func (suite *BannerTestSuite) TestViewWithoutName() {
+	t := suite.T()
+	banner := NewBanner("sandbox", "", "", "")
+	banner.SetWidth(30)
+
+	view := banner.View()
+
+	assert.Contains(t, view, "sandbox")
+	assert.False(t, strings.Contains(view, "·"))
+}
+
+func (suite *BannerTestSuite) TestViewWithIdentity() {
+	t := suite.T()
+	banner := NewBanner("payments-prod", "", "prod", "red")
+	banner.SetIdentity("breakglass@payments-prod.iam.gserviceaccount.com")
+	banner.SetWidth(100)
+
+	view := banner.View()
+
+	assert.Contains(t, view, "as breakglass@payments-prod.iam.gserviceaccount.com  PROD")
+	assert.Equal(t, 100, lipgloss.Width(view))
+}
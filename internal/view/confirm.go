@@ -1,7 +1,11 @@
 package view
 
 import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/erikgeiser/promptkit/confirmation"
 	"github.com/rs/zerolog/log"
 )
@@ -14,8 +18,12 @@ type Modal interface {
 
 type Confirm struct {
 	question     string
+	detail       string
 	message      any
 	confirmation *confirmation.Model
+	typed        string
+	input        textinput.Model
+	alertText    string
 }
 
 type ConfirmationResultMessage struct {
@@ -32,15 +40,50 @@ func NewConfirm(question string, message any) *Confirm {
 	return &Confirm{question: question, message: message, confirmation: confirmModel}
 }
 
+// SetDetail shows detail, such as a preview of the change, above the
+// question.
+func (c *Confirm) SetDetail(detail string) {
+	c.detail = detail
+}
+
+// RequireTyped asks to type expected, such as the project ID or the secret
+// name, instead of answering yes.
+func (c *Confirm) RequireTyped(expected string) {
+	c.typed = expected
+	c.input = textinput.New()
+	c.input.Prompt = "> "
+	c.input.CharLimit = 255
+	c.input.Width = 40
+}
+
 func (c *Confirm) View() string {
-	return c.confirmation.View()
+	body := c.confirmation.View()
+	if c.typed != "" {
+		lines := []string{c.question, fmt.Sprintf("Type %s to confirm, esc to cancel:", c.typed), c.input.View()}
+		if c.alertText != "" {
+			lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true).Render(c.alertText))
+		}
+		body = lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+	if c.detail != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, c.detail, "", body)
+	}
+	return body
 }
 
 func (c *Confirm) Init() tea.Cmd {
+	if c.typed != "" {
+		c.input.Focus()
+		return textinput.Blink
+	}
 	return c.confirmation.Init()
 }
 
 func (c *Confirm) Update(msg tea.Msg) (Modal, tea.Cmd) {
+	if c.typed != "" {
+		return c.updateTyped(msg)
+	}
+
 	m, cmd := c.confirmation.Update(msg)
 
 	if cmd != nil {
@@ -57,3 +100,21 @@ func (c *Confirm) Update(msg tea.Msg) (Modal, tea.Cmd) {
 	}
 	return c, nil
 }
+
+func (c *Confirm) updateTyped(msg tea.Msg) (Modal, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.Type == tea.KeyEnter {
+		if c.input.Value() != c.typed {
+			c.alertText = fmt.Sprintf("%q does not match %s", c.input.Value(), c.typed)
+			return c, nil
+		}
+		log.Info().Msgf("Typed confirmation of %s", c.typed)
+		return c, func() tea.Msg {
+			return ConfirmationResultMessage{true, c.message}
+		}
+	}
+
+	c.alertText = ""
+	var cmd tea.Cmd
+	c.input, cmd = c.input.Update(msg)
+	return c, cmd
+}
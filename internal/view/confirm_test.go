@@ -1,6 +1,7 @@
 package view
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -195,4 +196,38 @@ func (suite *ConfirmTestSuite) TestConfirmImplementsModalCorrectly() {
 	// Ensure the returned modal is still the same type
 	_, ok := updatedModal.(*Confirm)
 	assert.True(t, ok)
-}
\ No newline at end of file
+}
+func (suite *ConfirmTestSuite) TestSetDetail() {
+	t := suite.T()
+	confirm := NewConfirm("Apply?", "message")
+	confirm.SetDetail("+ roles/secretmanager.admin user:ana@example.com")
+	confirm.Init()
+
+	view := confirm.View()
+
+	assert.Contains(t, view, "+ roles/secretmanager.admin")
+	assert.Less(t, strings.Index(view, "+ roles"), strings.Index(view, "Apply?"))
+}
+
+func (suite *ConfirmTestSuite) TestRequireTyped() {
+	t := suite.T()
+	confirm := NewConfirm("Restore api-key?", "message")
+	confirm.RequireTyped("api-key")
+	confirm.Init()
+
+	assert.Contains(t, confirm.View(), "Type api-key to confirm")
+
+	for _, r := range "api" {
+		confirm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, cmd := confirm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd, "a partial name does not confirm")
+	assert.Contains(t, confirm.View(), "does not match")
+
+	for _, r := range "-key" {
+		confirm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, cmd = confirm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, ConfirmationResultMessage{Result: true, Msg: "message"}, cmd())
+}
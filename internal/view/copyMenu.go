@@ -0,0 +1,105 @@
+package view
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"smm/internal/ui"
+)
+
+// CopyFormat is what the copy menu copies of the selected secret.
+type CopyFormat string
+
+const (
+	CopyValue     CopyFormat = "value"
+	CopyKey       CopyFormat = "key"
+	CopyExport    CopyFormat = "export"
+	CopyBase64    CopyFormat = "base64"
+	CopyReference CopyFormat = "reference"
+)
+
+// CopyMessage asks to copy the selected secret in Format.
+type CopyMessage struct {
+	Format CopyFormat
+}
+
+type copyOption struct {
+	key    string
+	format CopyFormat
+	label  string
+}
+
+var copyOptions = []copyOption{
+	{"v", CopyValue, "Value"},
+	{"k", CopyKey, "Value of a single key"},
+	{"e", CopyExport, "export K=V lines"},
+	{"b", CopyBase64, "Base64"},
+	{"r", CopyReference, "sm:// reference"},
+}
+
+// CopyMenu chooses how to copy the selected secret or version, by moving to
+// an option or pressing its key.
+type CopyMenu struct {
+	target string
+	cursor int
+}
+
+// NewCopyMenu copies target, such as "version 3 of db-creds".
+func NewCopyMenu(target string) *CopyMenu {
+	return &CopyMenu{target: target}
+}
+
+func (c *CopyMenu) Init() tea.Cmd {
+	return nil
+}
+
+func (c *CopyMenu) Update(msg tea.Msg) (Modal, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return c, nil
+	}
+
+	switch key.String() {
+	case "up", "ctrl+p":
+		if c.cursor > 0 {
+			c.cursor--
+		}
+		return c, nil
+	case "down", "ctrl+n":
+		if c.cursor < len(copyOptions)-1 {
+			c.cursor++
+		}
+		return c, nil
+	case "enter":
+		return c, c.choose(copyOptions[c.cursor].format)
+	}
+
+	for _, option := range copyOptions {
+		if key.String() == option.key {
+			return c, c.choose(option.format)
+		}
+	}
+	return c, nil
+}
+
+func (c *CopyMenu) choose(format CopyFormat) tea.Cmd {
+	return func() tea.Msg {
+		return CopyMessage{Format: format}
+	}
+}
+
+func (c *CopyMenu) View() string {
+	styles := newSecretInfoStyles()
+	sections := []string{styles.title.Render("Copy " + c.target)}
+	for i, option := range copyOptions {
+		line := ui.StyleTag().Render(option.key) + " " + option.label
+		if i == c.cursor {
+			sections = append(sections, "› "+line)
+		} else {
+			sections = append(sections, "  "+line)
+		}
+	}
+	sections = append(sections, "", styles.footer.Render("enter or key: copy · esc: close"))
+	return lipgloss.NewStyle().Width(64).Render(strings.Join(sections, "\n"))
+}
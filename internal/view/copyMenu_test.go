@@ -0,0 +1,52 @@
+package view
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CopyMenuTestSuite struct {
+	suite.Suite
+}
+
+func TestCopyMenuSuite(t *testing.T) {
+	suite.Run(t, new(CopyMenuTestSuite))
+}
+
+func (suite *CopyMenuTestSuite) TestEnterCopiesSelectedOption() {
+	t := suite.T()
+	menu := NewCopyMenu("version 3 of db-creds")
+
+	_, cmd := menu.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, CopyMessage{Format: CopyValue}, cmd())
+
+	menu.Update(tea.KeyMsg{Type: tea.KeyDown})
+	menu.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd = menu.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, CopyMessage{Format: CopyExport}, cmd())
+}
+
+func (suite *CopyMenuTestSuite) TestOptionKey() {
+	t := suite.T()
+	menu := NewCopyMenu("db-creds")
+
+	_, cmd := menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	assert.Equal(t, CopyMessage{Format: CopyBase64}, cmd())
+
+	_, cmd = menu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	assert.Nil(t, cmd)
+}
+
+func (suite *CopyMenuTestSuite) TestView() {
+	t := suite.T()
+	menu := NewCopyMenu("version 3 of db-creds")
+
+	view := menu.View()
+
+	assert.Contains(t, view, "Copy version 3 of db-creds")
+	assert.Contains(t, view, "› ")
+	assert.Contains(t, view, "sm:// reference")
+}
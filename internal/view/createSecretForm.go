@@ -0,0 +1,108 @@
+package view
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rs/zerolog/log"
+	"smm/internal/client"
+	"smm/internal/ui"
+)
+
+// CreateSecretMessage asks to create a secret named Name. A non-zero TTL
+// deletes the secret once it has passed.
+type CreateSecretMessage struct {
+	Name string
+	TTL  time.Duration
+}
+
+type CreateSecretForm struct {
+	name       textinput.Model
+	ttl        textinput.Model
+	alertText  string
+	alertStyle lipgloss.Style
+}
+
+func NewCreateSecretForm() *CreateSecretForm {
+	name := textinput.New()
+	name.Prompt = "Name: "
+	name.Placeholder = "load-test-token"
+	name.Focus()
+	name.CharLimit = 255
+	name.Width = 40
+
+	ttl := textinput.New()
+	ttl.Prompt = "TTL:  "
+	ttl.Placeholder = "7d, 12h or empty to keep it"
+	ttl.CharLimit = 16
+	ttl.Width = 40
+
+	alertStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF6B6B")).
+		Bold(true)
+
+	return &CreateSecretForm{name: name, ttl: ttl, alertStyle: alertStyle}
+}
+
+func (p *CreateSecretForm) Init() tea.Cmd {
+	return nil
+}
+
+func (p *CreateSecretForm) Update(msg tea.Msg) (Modal, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyTab, tea.KeyShiftTab, tea.KeyUp, tea.KeyDown:
+			if p.name.Focused() {
+				p.name.Blur()
+				p.ttl.Focus()
+			} else {
+				p.ttl.Blur()
+				p.name.Focus()
+			}
+			return p, nil
+		case tea.KeyEnter:
+			name := p.name.Value()
+			log.Info().Msgf("CreateSecretForm: %v (ttl: %v)", name, p.ttl.Value())
+			if err := client.ValidateSecretName(name); err != nil {
+				p.alertText = err.Error()
+				return p, nil
+			}
+			var ttl time.Duration
+			if value := p.ttl.Value(); value != "" {
+				parsed, err := client.ParsePeriod(value)
+				if err != nil {
+					p.alertText = err.Error()
+					return p, nil
+				}
+				ttl = parsed
+			}
+			return p, func() tea.Msg {
+				return CreateSecretMessage{Name: name, TTL: ttl}
+			}
+		default:
+			p.alertText = ""
+		}
+	}
+
+	if p.name.Focused() {
+		p.name, cmd = p.name.Update(msg)
+	} else {
+		p.ttl, cmd = p.ttl.Update(msg)
+	}
+	return p, cmd
+}
+
+func (p *CreateSecretForm) View() string {
+	help := ui.StyleLow().Render("tab: next field · enter: write the first version in the editor")
+
+	view := lipgloss.JoinVertical(lipgloss.Left, p.name.View(), p.ttl.View(), help)
+	if p.alertText != "" {
+		view = lipgloss.JoinVertical(lipgloss.Left, view, p.alertStyle.Render(p.alertText))
+	}
+
+	return lipgloss.NewStyle().Width(64).Render(view)
+}
@@ -0,0 +1,58 @@
+package view
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CreateSecretFormTestSuite struct {
+	suite.Suite
+}
+
+func TestCreateSecretFormSuite(t *testing.T) {
+	suite.Run(t, new(CreateSecretFormTestSuite))
+}
+
+func (suite *CreateSecretFormTestSuite) TestCreateWithoutTTL() {
+	t := suite.T()
+	form := NewCreateSecretForm()
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("api-key")})
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, CreateSecretMessage{Name: "api-key"}, cmd())
+}
+
+func (suite *CreateSecretFormTestSuite) TestCreateWithTTL() {
+	t := suite.T()
+	form := NewCreateSecretForm()
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("contractor")})
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("7d")})
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, CreateSecretMessage{Name: "contractor", TTL: 7 * 24 * time.Hour}, cmd())
+}
+
+func (suite *CreateSecretFormTestSuite) TestInvalidInput() {
+	t := suite.T()
+	form := NewCreateSecretForm()
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("bad name")})
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd)
+	assert.Contains(t, form.View(), "letters, digits")
+
+	form = NewCreateSecretForm()
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("token")})
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("soon")})
+	_, cmd = form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd)
+	assert.Contains(t, form.View(), "invalid period")
+}
@@ -0,0 +1,170 @@
+package view
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"smm/internal/export"
+	"smm/internal/ui"
+)
+
+// ExportMessage asks to export the marked secrets, or the selected one, to
+// Path in Format. Recipients encrypt the file with age, Name is the name of a
+// Kubernetes Secret.
+type ExportMessage struct {
+	Format     export.Format
+	Path       string
+	Recipients []string
+	Name       string
+}
+
+// ExportForm chooses the format with left/right on its first line and asks
+// for the file, the age recipients and the Kubernetes name.
+type ExportForm struct {
+	target     string
+	format     int
+	focus      int
+	inputs     []textinput.Model
+	alertText  string
+	alertStyle lipgloss.Style
+}
+
+const (
+	exportPath = iota
+	exportRecipients
+	exportName
+)
+
+// NewExportForm exports target, such as "3 marked secrets".
+func NewExportForm(target string) *ExportForm {
+	path := textinput.New()
+	path.Prompt = "File:       "
+	path.SetValue(exportFileName(export.Formats[0]))
+	path.CharLimit = 255
+	path.Width = 40
+
+	recipients := textinput.New()
+	recipients.Prompt = "Encrypt to: "
+	recipients.Placeholder = "age1... recipients, empty for plain text"
+	recipients.CharLimit = 1024
+	recipients.Width = 40
+
+	name := textinput.New()
+	name.Prompt = "K8s name:   "
+	name.Placeholder = "named after the first secret"
+	name.CharLimit = 253
+	name.Width = 40
+
+	alertStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF6B6B")).
+		Bold(true)
+
+	return &ExportForm{target: target, inputs: []textinput.Model{path, recipients, name}, alertStyle: alertStyle}
+}
+
+func exportFileName(format export.Format) string {
+	return "secrets" + format.Extension()
+}
+
+func (p *ExportForm) Init() tea.Cmd {
+	return nil
+}
+
+func (p *ExportForm) Update(msg tea.Msg) (Modal, tea.Cmd) {
+	var cmd tea.Cmd
+	key, ok := msg.(tea.KeyMsg)
+	if ok {
+		p.alertText = ""
+		switch key.Type {
+		case tea.KeyTab, tea.KeyDown:
+			p.setFocus((p.focus + 1) % (len(p.inputs) + 1))
+			return p, nil
+		case tea.KeyShiftTab, tea.KeyUp:
+			p.setFocus((p.focus + len(p.inputs)) % (len(p.inputs) + 1))
+			return p, nil
+		case tea.KeyLeft, tea.KeyRight:
+			if p.focus == 0 {
+				step := 1
+				if key.Type == tea.KeyLeft {
+					step = len(export.Formats) - 1
+				}
+				p.setFormat((p.format + step) % len(export.Formats))
+				return p, nil
+			}
+		case tea.KeyEnter:
+			path := strings.TrimSpace(p.inputs[exportPath].Value())
+			if path == "" {
+				p.alertText = "Enter the file to write"
+				return p, nil
+			}
+			exportMsg := ExportMessage{
+				Format:     export.Formats[p.format],
+				Path:       path,
+				Recipients: strings.FieldsFunc(p.inputs[exportRecipients].Value(), func(r rune) bool { return r == ',' || r == ' ' }),
+				Name:       strings.TrimSpace(p.inputs[exportName].Value()),
+			}
+			return p, func() tea.Msg {
+				return exportMsg
+			}
+		}
+	}
+
+	if p.focus > 0 {
+		p.inputs[p.focus-1], cmd = p.inputs[p.focus-1].Update(msg)
+	}
+	return p, cmd
+}
+
+// setFocus focuses the format line at 0 or the input before focus.
+func (p *ExportForm) setFocus(focus int) {
+	p.focus = focus
+	for i := range p.inputs {
+		if i == focus-1 {
+			p.inputs[i].Focus()
+		} else {
+			p.inputs[i].Blur()
+		}
+	}
+}
+
+// setFormat switches format, renaming the file if it still has the name of
+// the previous one.
+func (p *ExportForm) setFormat(format int) {
+	if p.inputs[exportPath].Value() == exportFileName(export.Formats[p.format]) {
+		p.inputs[exportPath].SetValue(exportFileName(export.Formats[format]))
+	}
+	p.format = format
+}
+
+func (p *ExportForm) View() string {
+	styles := newSecretInfoStyles()
+
+	var formats []string
+	for i, format := range export.Formats {
+		if i == p.format {
+			formats = append(formats, ui.StyleTag().Render(string(format)))
+		} else {
+			formats = append(formats, ui.StyleLow().Render(string(format)))
+		}
+	}
+	cursor := "  "
+	if p.focus == 0 {
+		cursor = "› "
+	}
+
+	sections := []string{
+		styles.title.Render("Export " + p.target),
+		cursor + "Format: " + strings.Join(formats, " "),
+	}
+	for _, input := range p.inputs {
+		sections = append(sections, "  "+input.View())
+	}
+	sections = append(sections, "", styles.footer.Render("←/→: format · tab: next field · enter: write with 0600 permissions"))
+	if p.alertText != "" {
+		sections = append(sections, p.alertStyle.Render(p.alertText))
+	}
+
+	return lipgloss.NewStyle().Width(72).Render(strings.Join(sections, "\n"))
+}
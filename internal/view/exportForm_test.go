@@ -0,0 +1,64 @@
+package view
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"smm/internal/export"
+)
+
+type ExportFormTestSuite struct {
+	suite.Suite
+}
+
+func TestExportFormSuite(t *testing.T) {
+	suite.Run(t, new(ExportFormTestSuite))
+}
+
+func (suite *ExportFormTestSuite) TestDefaults() {
+	t := suite.T()
+	form := NewExportForm("db-creds")
+
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, ExportMessage{Format: export.Dotenv, Path: "secrets.env", Recipients: []string{}}, cmd())
+	assert.Contains(t, form.View(), "Export db-creds")
+}
+
+func (suite *ExportFormTestSuite) TestFormatRenamesFile() {
+	t := suite.T()
+	form := NewExportForm("2 marked secrets")
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRight})
+	form.Update(tea.KeyMsg{Type: tea.KeyRight})
+	form.Update(tea.KeyMsg{Type: tea.KeyRight})
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("age1a, age1b")})
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("app")})
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, ExportMessage{
+		Format:     export.Kubernetes,
+		Path:       "secrets.yaml",
+		Recipients: []string{"age1a", "age1b"},
+		Name:       "app",
+	}, cmd())
+}
+
+func (suite *ExportFormTestSuite) TestEmptyPath() {
+	t := suite.T()
+	form := NewExportForm("db-creds")
+
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	for range "secrets.env" {
+		form.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Nil(t, cmd)
+	assert.Contains(t, form.View(), "Enter the file to write")
+}
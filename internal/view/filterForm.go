@@ -0,0 +1,68 @@
+package view
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rs/zerolog/log"
+	"smm/internal/ui"
+)
+
+// FilterMessage applies a ListSecrets filter to the project. Save also keeps
+// it in the config for the next sessions.
+type FilterMessage struct {
+	Filter string
+	Save   bool
+}
+
+type FilterForm struct {
+	teaView textinput.Model
+}
+
+func NewFilterForm(filter string) *FilterForm {
+	form := textinput.New()
+	form.Prompt = "Filter: "
+	form.ShowSuggestions = false
+	form.Placeholder = "labels.team=payments AND name:api"
+	form.SetValue(filter)
+	form.Focus()
+	form.CharLimit = 512
+	form.Width = 56
+
+	return &FilterForm{teaView: form}
+}
+
+func (p *FilterForm) Init() tea.Cmd {
+	return nil
+}
+
+func (p *FilterForm) Value() string {
+	return p.teaView.Value()
+}
+
+func (p *FilterForm) Update(msg tea.Msg) (Modal, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter", "ctrl+s":
+			filter := p.teaView.Value()
+			save := msg.String() == "ctrl+s"
+			log.Info().Msgf("FilterForm: %v (save: %v)", filter, save)
+			return p, func() tea.Msg {
+				return FilterMessage{Filter: filter, Save: save}
+			}
+		}
+	}
+
+	p.teaView, cmd = p.teaView.Update(msg)
+	return p, cmd
+}
+
+func (p *FilterForm) View() string {
+	options := ui.StyleLow().Render("enter: this session · ctrl+s: save for the project · empty: no filter")
+	syntax := ui.StyleLow().Render("name:api labels.team=x labels.env:* create_time>2024-01-01 NOT ( )")
+
+	view := lipgloss.JoinVertical(lipgloss.Left, p.teaView.View(), options, syntax)
+	return lipgloss.NewStyle().Width(80).Render(view)
+}
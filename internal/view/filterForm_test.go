@@ -0,0 +1,65 @@
+package view
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FilterFormTestSuite struct {
+	suite.Suite
+	filterForm *FilterForm
+}
+
+func (suite *FilterFormTestSuite) SetupTest() {
+	suite.filterForm = NewFilterForm("labels.team=payments")
+}
+
+func TestFilterFormSuite(t *testing.T) {
+	suite.Run(t, new(FilterFormTestSuite))
+}
+
+func (suite *FilterFormTestSuite) TestNewFilterForm() {
+	t := suite.T()
+
+	assert.Equal(t, "labels.team=payments", suite.filterForm.Value())
+	assert.Nil(t, suite.filterForm.Init())
+}
+
+func (suite *FilterFormTestSuite) TestUpdate_Enter() {
+	t := suite.T()
+
+	_, cmd := suite.filterForm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.NotNil(t, cmd)
+	assert.Equal(t, FilterMessage{Filter: "labels.team=payments"}, cmd())
+}
+
+func (suite *FilterFormTestSuite) TestUpdate_Save() {
+	t := suite.T()
+
+	_, cmd := suite.filterForm.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+
+	assert.NotNil(t, cmd)
+	assert.Equal(t, FilterMessage{Filter: "labels.team=payments", Save: true}, cmd())
+}
+
+func (suite *FilterFormTestSuite) TestUpdate_Typing() {
+	t := suite.T()
+	form := NewFilterForm("")
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("name:api")})
+
+	assert.Equal(t, "name:api", form.Value())
+}
+
+func (suite *FilterFormTestSuite) TestView() {
+	t := suite.T()
+
+	view := suite.filterForm.View()
+
+	assert.Contains(t, view, "Filter:")
+	assert.Contains(t, view, "ctrl+s")
+}
@@ -1,6 +1,8 @@
 package view
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,11 +24,90 @@ type keyMap struct {
 	ProjectId  key.Binding
 	Versions   key.Binding
 	Info       key.Binding
+	ApiFilter  key.Binding
+	Alias      key.Binding
+	Settings   key.Binding
+	NewSecret  key.Binding
+	Iam        key.Binding
+	Reveal     key.Binding
+	Fetch      key.Binding
+	Export     key.Binding
+	Import     key.Binding
 	Quit       key.Binding
 }
 
 type Help struct {
 	teaView help.Model
+	denied  map[string]string
+}
+
+// deniedKeyMap annotates the bindings of actions the user cannot perform.
+type deniedKeyMap struct {
+	keyMap
+	denied map[string]string
+}
+
+// ShortHelp adds the denied actions missing from the short help before quit,
+// as the full help is never shown.
+func (k deniedKeyMap) ShortHelp() []key.Binding {
+	short := k.keyMap.ShortHelp()
+	shown := map[string]bool{}
+	for _, binding := range short {
+		shown[binding.Help().Key] = true
+	}
+
+	var denied []key.Binding
+	for _, group := range k.keyMap.FullHelp() {
+		for _, binding := range group {
+			if !shown[binding.Help().Key] && k.isDenied(binding) {
+				shown[binding.Help().Key] = true
+				denied = append(denied, binding)
+			}
+		}
+	}
+
+	quit := short[len(short)-1]
+	bindings := append(append(short[:len(short)-1:len(short)-1], denied...), quit)
+	return k.annotate(bindings)
+}
+
+func (k deniedKeyMap) isDenied(binding key.Binding) bool {
+	for _, keyName := range binding.Keys() {
+		if _, ok := k.denied[keyName]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (k deniedKeyMap) FullHelp() [][]key.Binding {
+	var groups [][]key.Binding
+	for _, group := range k.keyMap.FullHelp() {
+		groups = append(groups, k.annotate(group))
+	}
+	return groups
+}
+
+func (k deniedKeyMap) annotate(bindings []key.Binding) []key.Binding {
+	annotated := make([]key.Binding, len(bindings))
+	for i, binding := range bindings {
+		var denied []string
+		for _, keyName := range binding.Keys() {
+			if _, ok := k.denied[keyName]; ok {
+				denied = append(denied, keyName)
+			}
+		}
+
+		annotated[i] = binding
+		switch {
+		case len(denied) == 0:
+		case len(denied) == len(binding.Keys()):
+			annotated[i].SetHelp(binding.Help().Key, binding.Help().Desc+" (no permission)")
+		default:
+			annotated[i].SetHelp(binding.Help().Key, binding.Help().Desc+" ("+strings.Join(denied, "/")+": no permission)")
+		}
+	}
+	return annotated
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
@@ -40,7 +121,7 @@ func (k keyMap) ShortHelp() []key.Binding {
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Left, k.Right},
-		{k.NewVersion, k.Info, k.ProjectId},
+		{k.NewVersion, k.NewSecret, k.Info, k.Iam, k.Settings, k.Alias, k.Reveal, k.Fetch, k.Export, k.Import, k.ApiFilter, k.ProjectId},
 		{k.Help, k.Quit},
 	}
 }
@@ -82,7 +163,7 @@ var keys = keyMap{
 	),
 	Copy: key.NewBinding(
 		key.WithKeys("c"),
-		key.WithHelp("c", "copy"),
+		key.WithHelp("c", "copy menu"),
 	),
 	Restore: key.NewBinding(
 		key.WithKeys("r"),
@@ -104,6 +185,42 @@ var keys = keyMap{
 		key.WithKeys("i"),
 		key.WithHelp("i", "Secret Info"),
 	),
+	Settings: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "rotation, topics & expiry"),
+	),
+	Iam: key.NewBinding(
+		key.WithKeys("I"),
+		key.WithHelp("I", "IAM policy"),
+	),
+	NewSecret: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "new secret"),
+	),
+	Alias: key.NewBinding(
+		key.WithKeys("a", "A"),
+		key.WithHelp("a/A", "open/assign alias"),
+	),
+	Reveal: key.NewBinding(
+		key.WithKeys("m", "M"),
+		key.WithHelp("m/M", "reveal secret/key"),
+	),
+	Fetch: key.NewBinding(
+		key.WithKeys("o", "O"),
+		key.WithHelp("o/O", "fetch value/metadata only"),
+	),
+	Export: key.NewBinding(
+		key.WithKeys(" ", "x"),
+		key.WithHelp("space/x", "mark/export"),
+	),
+	Import: key.NewBinding(
+		key.WithKeys("U"),
+		key.WithHelp("U", "import secrets"),
+	),
+	ApiFilter: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "API filter"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("ctrl+c"),
 		key.WithHelp("ctrl+c", "quit"),
@@ -128,7 +245,16 @@ func (h *Help) SetHeight(w int) {
 	h.teaView.Width = w
 }
 
+// SetDenied marks the actions bound to the keys of denied as not permitted,
+// mapped to the reason; nil clears every mark.
+func (h *Help) SetDenied(denied map[string]string) {
+	h.denied = denied
+}
+
 func (h *Help) View() string {
+	if len(h.denied) > 0 {
+		return h.teaView.View(deniedKeyMap{keyMap: keys, denied: h.denied})
+	}
 	return h.teaView.View(keys)
 }
 
@@ -89,9 +89,9 @@ func TestKeyMapFullHelp(t *testing.T) {
 	fullHelp := keys.FullHelp()
 
 	assert.Len(t, fullHelp, 3)
-	assert.Len(t, fullHelp[0], 4) // Movement keys
-	assert.Len(t, fullHelp[1], 3) // Action keys (now includes Info)
-	assert.Len(t, fullHelp[2], 2) // Help and quit keys
+	assert.Len(t, fullHelp[0], 4)  // Movement keys
+	assert.Len(t, fullHelp[1], 12) // Action keys (now includes new secret, Info, IAM, settings, aliases, reveal, fetch, export, import and API filter)
+	assert.Len(t, fullHelp[2], 2)  // Help and quit keys
 }
 
 func TestKeyBindings(t *testing.T) {
@@ -129,7 +129,7 @@ func TestKeyBindings(t *testing.T) {
 	assert.Equal(t, "new version", keys.NewVersion.Help().Desc)
 
 	assert.Equal(t, "c", keys.Copy.Keys()[0])
-	assert.Equal(t, "copy", keys.Copy.Help().Desc)
+	assert.Equal(t, "copy menu", keys.Copy.Help().Desc)
 
 	assert.Equal(t, "r", keys.Restore.Keys()[0])
 	assert.Equal(t, "restore", keys.Restore.Help().Desc)
@@ -205,4 +205,38 @@ func (suite *HelpTestSuite) TestUpdateWithDifferentMessages() {
 	// Both should return some result (cmd might be nil, that's ok)
 	_ = cmd1
 	_ = cmd2
-}
\ No newline at end of file
+}
+
+func (suite *HelpTestSuite) TestSetDenied() {
+	t := suite.T()
+	suite.help.SetWidth(200)
+
+	suite.help.SetDenied(map[string]string{"n": "missing secretmanager.versions.add"})
+	assert.Contains(t, suite.help.View(), "new version (no permission)")
+
+	suite.help.SetDenied(nil)
+	assert.NotContains(t, suite.help.View(), "no permission")
+}
+
+func TestDeniedKeyMapAnnotatesPartialBindings(t *testing.T) {
+	denied := deniedKeyMap{keyMap: keys, denied: map[string]string{"A": "missing secretmanager.versions.add"}}
+
+	fullHelp := denied.FullHelp()
+
+	assert.Equal(t, "open/assign alias (A: no permission)", fullHelp[1][5].Help().Desc)
+	assert.Equal(t, "new version", fullHelp[1][0].Help().Desc)
+	assert.Equal(t, "open/assign alias", keys.Alias.Help().Desc)
+}
+
+func TestDeniedKeyMapShortHelpShowsDeniedActions(t *testing.T) {
+	denied := deniedKeyMap{keyMap: keys, denied: map[string]string{"e": "read-only", "I": "read-only", "n": "read-only"}}
+
+	shortHelp := denied.ShortHelp()
+
+	assert.Len(t, shortHelp, len(keys.ShortHelp())+2)
+	assert.Equal(t, "new version (no permission)", shortHelp[3].Help().Desc)
+	assert.Equal(t, "IAM policy (no permission)", shortHelp[len(shortHelp)-3].Help().Desc)
+	assert.Equal(t, "rotation, topics & expiry (no permission)", shortHelp[len(shortHelp)-2].Help().Desc)
+	assert.Equal(t, keys.Quit.Help(), shortHelp[len(shortHelp)-1].Help())
+	assert.Len(t, keys.ShortHelp(), 9)
+}
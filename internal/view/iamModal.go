@@ -0,0 +1,210 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"smm/internal/client"
+	"smm/internal/ui"
+)
+
+// IamChangeMessage asks to grant Role to Member or, with Remove, to revoke
+// the grant of the binding with Condition.
+type IamChangeMessage struct {
+	FullPath  string
+	Role      string
+	Member    string
+	Condition client.Condition
+	Remove    bool
+}
+
+// iamRow is a member of a binding, the unit the modal selects and removes.
+type iamRow struct {
+	binding   int
+	role      string
+	member    string
+	condition client.Condition
+}
+
+// IamModal lists the role bindings of a secret and edits its members.
+type IamModal struct {
+	fullPath   string
+	secretName string
+	rows       []iamRow
+	cursor     int
+	adding     bool
+	denied     string
+	role       textinput.Model
+	member     textinput.Model
+	alertText  string
+	alertStyle lipgloss.Style
+}
+
+func NewIamModal(fullPath, secretName string, policy client.Policy) *IamModal {
+	var rows []iamRow
+	for i, binding := range policy.Bindings {
+		for _, member := range binding.Members {
+			rows = append(rows, iamRow{binding: i, role: binding.Role, member: member, condition: binding.Condition})
+		}
+	}
+
+	role := textinput.New()
+	role.Prompt = "Role:   "
+	role.SetValue("roles/secretmanager.secretAccessor")
+	role.CharLimit = 128
+	role.Width = 48
+
+	member := textinput.New()
+	member.Prompt = "Member: "
+	member.Placeholder = "user:ana@example.com"
+	member.CharLimit = 256
+	member.Width = 48
+
+	alertStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF6B6B")).
+		Bold(true)
+
+	return &IamModal{fullPath: fullPath, secretName: secretName, rows: rows, role: role, member: member, alertStyle: alertStyle}
+}
+
+// SetDenied keeps the bindings readable but refuses to change them, showing
+// reason instead.
+func (m *IamModal) SetDenied(reason string) {
+	m.denied = reason
+}
+
+func (m *IamModal) Init() tea.Cmd {
+	return nil
+}
+
+func (m *IamModal) Update(msg tea.Msg) (Modal, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.adding {
+		return m.updateForm(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+	case "a":
+		if m.deny() {
+			return m, nil
+		}
+		m.adding = true
+		m.member.Focus()
+	case "d", "delete":
+		if m.deny() || len(m.rows) == 0 {
+			return m, nil
+		}
+		row := m.rows[m.cursor]
+		change := IamChangeMessage{FullPath: m.fullPath, Role: row.role, Member: row.member, Condition: row.condition, Remove: true}
+		return m, func() tea.Msg { return change }
+	}
+	return m, nil
+}
+
+// deny shows why the bindings cannot be changed, reporting whether they
+// cannot.
+func (m *IamModal) deny() bool {
+	if m.denied == "" {
+		return false
+	}
+	m.alertText = m.denied
+	return true
+}
+
+func (m *IamModal) updateForm(msg tea.KeyMsg) (Modal, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg.Type {
+	case tea.KeyTab, tea.KeyShiftTab:
+		if m.member.Focused() {
+			m.member.Blur()
+			m.role.Focus()
+		} else {
+			m.role.Blur()
+			m.member.Focus()
+		}
+		return m, nil
+	case tea.KeyEnter:
+		role, member := strings.TrimSpace(m.role.Value()), strings.TrimSpace(m.member.Value())
+		if err := client.ValidateRole(role); err != nil {
+			m.alertText = err.Error()
+			return m, nil
+		}
+		if err := client.ValidateMember(member); err != nil {
+			m.alertText = err.Error()
+			return m, nil
+		}
+		change := IamChangeMessage{FullPath: m.fullPath, Role: role, Member: member}
+		return m, func() tea.Msg { return change }
+	default:
+		m.alertText = ""
+	}
+
+	if m.member.Focused() {
+		m.member, cmd = m.member.Update(msg)
+	} else {
+		m.role, cmd = m.role.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m *IamModal) View() string {
+	styles := newSecretInfoStyles()
+	sections := []string{styles.title.Render(fmt.Sprintf("IAM Policy: %s", m.secretName))}
+
+	if len(m.rows) == 0 {
+		sections = append(sections, styles.value.Render("No role bindings"))
+	}
+	for i, row := range m.rows {
+		if i == 0 || row.binding != m.rows[i-1].binding {
+			header := row.role
+			if row.condition.Expression != "" {
+				header += " " + ui.StyleTag().Render("if "+conditionLabel(row.condition))
+			}
+			sections = append(sections, styles.label.Render(header))
+		}
+
+		if i == m.cursor && !m.adding {
+			sections = append(sections, "  "+ui.StyleSelected().Render(row.member))
+		} else {
+			sections = append(sections, styles.value.Render("    "+row.member))
+		}
+	}
+
+	sections = append(sections, "")
+	if m.adding {
+		sections = append(sections, m.role.View(), m.member.View())
+		sections = append(sections, styles.footer.Render("tab: next field · enter: preview the change · esc: close"))
+	} else if m.denied != "" {
+		sections = append(sections, styles.footer.Render("esc: close"))
+	} else {
+		sections = append(sections, styles.footer.Render("a: add member · d: remove member · esc: close"))
+	}
+	if m.alertText != "" {
+		sections = append(sections, m.alertStyle.Render(m.alertText))
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// conditionLabel names a condition by its title, or by its expression when
+// it has none.
+func conditionLabel(condition client.Condition) string {
+	if condition.Title != "" {
+		return condition.Title + ": " + condition.Expression
+	}
+	return condition.Expression
+}
@@ -0,0 +1,91 @@
+package view
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"smm/internal/client"
+)
+
+type IamModalTestSuite struct {
+	suite.Suite
+	modal     *IamModal
+	condition client.Condition
+}
+
+func TestIamModalSuite(t *testing.T) {
+	suite.Run(t, new(IamModalTestSuite))
+}
+
+func (suite *IamModalTestSuite) SetupTest() {
+	suite.condition = client.Condition{Title: "Temporary", Expression: "request.time < timestamp(\"2030-01-01T00:00:00Z\")"}
+	policy := client.Policy{Bindings: []client.Binding{
+		{Role: "roles/secretmanager.admin", Members: []string{"group:platform@example.com"}},
+		{Role: "roles/secretmanager.secretAccessor", Members: []string{"user:bob@example.com"}, Condition: suite.condition},
+	}}
+	suite.modal = NewIamModal("projects/p/secrets/api-key", "api-key", policy)
+}
+
+func (suite *IamModalTestSuite) TestView() {
+	t := suite.T()
+
+	view := suite.modal.View()
+
+	assert.Contains(t, view, "IAM Policy: api-key")
+	assert.Contains(t, view, "roles/secretmanager.admin")
+	assert.Contains(t, view, "group:platform@example.com")
+	assert.Contains(t, view, "if Temporary: request.time")
+	assert.Contains(t, NewIamModal("p", "empty", client.Policy{}).View(), "No role bindings")
+}
+
+func (suite *IamModalTestSuite) TestRemove() {
+	t := suite.T()
+
+	suite.modal.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := suite.modal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+
+	assert.Equal(t, IamChangeMessage{
+		FullPath:  "projects/p/secrets/api-key",
+		Role:      "roles/secretmanager.secretAccessor",
+		Member:    "user:bob@example.com",
+		Condition: suite.condition,
+		Remove:    true,
+	}, cmd())
+}
+
+func (suite *IamModalTestSuite) TestDenied() {
+	t := suite.T()
+	suite.modal.SetDenied("Missing secretmanager.secrets.setIamPolicy on api-key")
+
+	_, cmd := suite.modal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	assert.Nil(t, cmd)
+	suite.modal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	assert.False(t, suite.modal.adding)
+
+	view := suite.modal.View()
+	assert.Contains(t, view, "group:platform@example.com")
+	assert.Contains(t, view, "Missing secretmanager.secrets.setIamPolicy")
+	assert.NotContains(t, view, "a: add member")
+}
+
+func (suite *IamModalTestSuite) TestAdd() {
+	t := suite.T()
+
+	suite.modal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	suite.modal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ana@example.com")})
+	_, cmd := suite.modal.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd)
+	assert.Contains(t, suite.modal.View(), "member must start with")
+
+	suite.modal.Update(tea.KeyMsg{Type: tea.KeyHome})
+	suite.modal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("user:")})
+	_, cmd = suite.modal.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, IamChangeMessage{
+		FullPath: "projects/p/secrets/api-key",
+		Role:     "roles/secretmanager.secretAccessor",
+		Member:   "user:ana@example.com",
+	}, cmd())
+}
@@ -0,0 +1,102 @@
+package view
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"smm/internal/client"
+	"smm/internal/ui"
+)
+
+// ImportMessage asks to plan the import of the file or directory at Path,
+// adding Labels to every imported secret.
+type ImportMessage struct {
+	Path   string
+	Labels map[string]string
+}
+
+type ImportForm struct {
+	path       textinput.Model
+	labels     textinput.Model
+	alertText  string
+	alertStyle lipgloss.Style
+}
+
+func NewImportForm() *ImportForm {
+	path := textinput.New()
+	path.Prompt = "From:   "
+	path.Placeholder = ".env, secrets.json, secrets.yaml or a directory"
+	path.Focus()
+	path.CharLimit = 255
+	path.Width = 48
+
+	labels := textinput.New()
+	labels.Prompt = "Labels: "
+	labels.Placeholder = "team=payments,env=dev"
+	labels.CharLimit = 512
+	labels.Width = 48
+
+	alertStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF6B6B")).
+		Bold(true)
+
+	return &ImportForm{path: path, labels: labels, alertStyle: alertStyle}
+}
+
+func (p *ImportForm) Init() tea.Cmd {
+	return nil
+}
+
+func (p *ImportForm) Update(msg tea.Msg) (Modal, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyTab, tea.KeyShiftTab, tea.KeyUp, tea.KeyDown:
+			if p.path.Focused() {
+				p.path.Blur()
+				p.labels.Focus()
+			} else {
+				p.labels.Blur()
+				p.path.Focus()
+			}
+			return p, nil
+		case tea.KeyEnter:
+			path := strings.TrimSpace(p.path.Value())
+			if path == "" {
+				p.alertText = "Enter the file or directory to import"
+				return p, nil
+			}
+			labels, err := client.ParseLabels(p.labels.Value())
+			if err != nil {
+				p.alertText = err.Error()
+				return p, nil
+			}
+			return p, func() tea.Msg {
+				return ImportMessage{Path: path, Labels: labels}
+			}
+		default:
+			p.alertText = ""
+		}
+	}
+
+	if p.path.Focused() {
+		p.path, cmd = p.path.Update(msg)
+	} else {
+		p.labels, cmd = p.labels.Update(msg)
+	}
+	return p, cmd
+}
+
+func (p *ImportForm) View() string {
+	help := ui.StyleLow().Render("tab: next field · enter: show what will be imported")
+
+	view := lipgloss.JoinVertical(lipgloss.Left, p.path.View(), p.labels.View(), help)
+	if p.alertText != "" {
+		view = lipgloss.JoinVertical(lipgloss.Left, view, p.alertStyle.Render(p.alertText))
+	}
+
+	return lipgloss.NewStyle().Width(64).Render(view)
+}
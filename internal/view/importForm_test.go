@@ -0,0 +1,45 @@
+package view
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImportFormTestSuite struct {
+	suite.Suite
+}
+
+func TestImportFormSuite(t *testing.T) {
+	suite.Run(t, new(ImportFormTestSuite))
+}
+
+func (suite *ImportFormTestSuite) TestImport() {
+	t := suite.T()
+	form := NewImportForm()
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("./secrets")})
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("team=payments")})
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, ImportMessage{Path: "./secrets", Labels: map[string]string{"team": "payments"}}, cmd())
+}
+
+func (suite *ImportFormTestSuite) TestInvalid() {
+	t := suite.T()
+	form := NewImportForm()
+
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd)
+	assert.Contains(t, form.View(), "Enter the file or directory")
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(".env")})
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Team=x")})
+	_, cmd = form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd)
+	assert.Contains(t, form.View(), "label key")
+}
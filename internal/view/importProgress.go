@@ -0,0 +1,86 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"smm/internal/ui"
+)
+
+// importFailuresShown is how many failed items the progress lists, the
+// latest ones first.
+const importFailuresShown = 8
+
+// ImportProgress shows an import being applied item by item and, once it
+// is done, its summary and the items that failed.
+type ImportProgress struct {
+	total    int
+	done     int
+	failures []string
+	summary  string
+	bar      progress.Model
+}
+
+func NewImportProgress(total int) *ImportProgress {
+	return &ImportProgress{total: total, bar: progress.New(progress.WithDefaultGradient(), progress.WithWidth(56))}
+}
+
+// Advance records that the item name was imported, or failed with err.
+func (p *ImportProgress) Advance(name string, err error) {
+	p.done++
+	if err != nil {
+		p.failures = append(p.failures, fmt.Sprintf("%s: %v", name, err))
+	}
+}
+
+// Finish shows summary in place of the progress bar.
+func (p *ImportProgress) Finish(summary string) {
+	p.summary = summary
+}
+
+func (p *ImportProgress) Init() tea.Cmd {
+	return nil
+}
+
+func (p *ImportProgress) Update(msg tea.Msg) (Modal, tea.Cmd) {
+	return p, nil
+}
+
+func (p *ImportProgress) View() string {
+	styles := newSecretInfoStyles()
+
+	title := fmt.Sprintf("Importing %d/%d", p.done, p.total)
+	if p.summary != "" {
+		title = "Import finished"
+	}
+	sections := []string{styles.title.Render(title)}
+	if p.summary != "" {
+		sections = append(sections, p.summary)
+	} else {
+		percent := 1.0
+		if p.total > 0 {
+			percent = float64(p.done) / float64(p.total)
+		}
+		sections = append(sections, p.bar.ViewAs(percent))
+	}
+
+	if len(p.failures) > 0 {
+		sections = append(sections, "", ui.StyleWarning().Render(fmt.Sprintf("%d failed:", len(p.failures))))
+		for i := len(p.failures) - 1; i >= 0 && i >= len(p.failures)-importFailuresShown; i-- {
+			sections = append(sections, "  "+p.failures[i])
+		}
+		if len(p.failures) > importFailuresShown {
+			sections = append(sections, ui.StyleLow().Render(fmt.Sprintf("  … and %d more in the log", len(p.failures)-importFailuresShown)))
+		}
+	}
+
+	footer := "esc: hide, the import goes on"
+	if p.summary != "" {
+		footer = "esc: close"
+	}
+	sections = append(sections, "", styles.footer.Render(footer))
+	return lipgloss.NewStyle().Width(64).Render(strings.Join(sections, "\n"))
+}
@@ -0,0 +1,44 @@
+package view
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImportProgressTestSuite struct {
+	suite.Suite
+}
+
+func TestImportProgressSuite(t *testing.T) {
+	suite.Run(t, new(ImportProgressTestSuite))
+}
+
+func (suite *ImportProgressTestSuite) TestProgress() {
+	t := suite.T()
+	progress := NewImportProgress(3)
+
+	progress.Advance("api-key", nil)
+	progress.Advance("db-url", errors.New("permission denied"))
+
+	view := progress.View()
+	assert.Contains(t, view, "Importing 2/3")
+	assert.Contains(t, view, "1 failed")
+	assert.Contains(t, view, "db-url: permission denied")
+	assert.Contains(t, view, "the import goes on")
+}
+
+func (suite *ImportProgressTestSuite) TestFinish() {
+	t := suite.T()
+	progress := NewImportProgress(1)
+
+	progress.Advance("api-key", nil)
+	progress.Finish("1 created, 0 updated, 0 unchanged")
+
+	view := progress.View()
+	assert.Contains(t, view, "Import finished")
+	assert.Contains(t, view, "1 created")
+	assert.NotContains(t, view, "failed")
+}
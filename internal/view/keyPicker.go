@@ -0,0 +1,115 @@
+package view
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+	"smm/internal/ui"
+)
+
+// maxKeyRows is the number of keys listed at once.
+const maxKeyRows = 10
+
+// RevealKeyMessage asks to show the value of Key in the masked secret.
+type RevealKeyMessage struct {
+	Key string
+}
+
+// KeyPicker picks a key of a secret, filtered fuzzily by the input, and
+// sends the message pick returns for it.
+type KeyPicker struct {
+	teaView textinput.Model
+	hint    string
+	keys    []string
+	rows    []string
+	cursor  int
+	pick    func(key string) tea.Msg
+}
+
+func NewKeyPicker(prompt, hint string, keys []string, pick func(key string) tea.Msg) *KeyPicker {
+	form := textinput.New()
+	form.Prompt = prompt
+	form.Placeholder = "filter keys"
+	form.Focus()
+	form.CharLimit = 128
+	form.Width = 48
+
+	return &KeyPicker{teaView: form, hint: hint, keys: keys, rows: keys, pick: pick}
+}
+
+// NewRevealKeyForm picks the key of a masked secret to reveal.
+func NewRevealKeyForm(keys []string) *KeyPicker {
+	return NewKeyPicker("Reveal key: ", "enter: reveal until another secret is selected", keys, func(key string) tea.Msg {
+		return RevealKeyMessage{Key: key}
+	})
+}
+
+func (r *KeyPicker) Init() tea.Cmd {
+	return nil
+}
+
+func (r *KeyPicker) Update(msg tea.Msg) (Modal, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "ctrl+p":
+			if r.cursor > 0 {
+				r.cursor--
+			}
+			return r, nil
+		case "down", "ctrl+n":
+			if r.cursor < len(r.rows)-1 {
+				r.cursor++
+			}
+			return r, nil
+		case "enter":
+			if len(r.rows) == 0 {
+				return r, nil
+			}
+			key, pick := r.rows[r.cursor], r.pick
+			return r, func() tea.Msg {
+				return pick(key)
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	previous := r.teaView.Value()
+	r.teaView, cmd = r.teaView.Update(msg)
+	if r.teaView.Value() != previous {
+		r.filter()
+	}
+	return r, cmd
+}
+
+func (r *KeyPicker) filter() {
+	r.cursor = 0
+	pattern := strings.TrimSpace(r.teaView.Value())
+	if pattern == "" {
+		r.rows = r.keys
+		return
+	}
+	r.rows = nil
+	for _, match := range fuzzy.Find(pattern, r.keys) {
+		r.rows = append(r.rows, match.Str)
+	}
+}
+
+func (r *KeyPicker) View() string {
+	sections := []string{r.teaView.View(), ""}
+	if len(r.rows) == 0 {
+		sections = append(sections, ui.StyleLow().Render("No matching key"))
+	}
+	start := max(0, min(r.cursor-maxKeyRows/2, len(r.rows)-maxKeyRows))
+	for i := start; i < len(r.rows) && i < start+maxKeyRows; i++ {
+		if i == r.cursor {
+			sections = append(sections, "› "+r.rows[i])
+		} else {
+			sections = append(sections, "  "+r.rows[i])
+		}
+	}
+	sections = append(sections, "", ui.StyleLow().Render(r.hint+" · esc: close"))
+	return lipgloss.NewStyle().Width(64).Render(strings.Join(sections, "\n"))
+}
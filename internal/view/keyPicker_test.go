@@ -0,0 +1,63 @@
+package view
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyPickerTestSuite struct {
+	suite.Suite
+}
+
+func TestKeyPickerSuite(t *testing.T) {
+	suite.Run(t, new(KeyPickerTestSuite))
+}
+
+func (suite *KeyPickerTestSuite) TestSelectFirstKey() {
+	t := suite.T()
+	form := NewRevealKeyForm([]string{"db.host", "db.password", "token"})
+
+	form.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, RevealKeyMessage{Key: "db.password"}, cmd())
+	assert.Contains(t, form.View(), "› db.password")
+}
+
+func (suite *KeyPickerTestSuite) TestFilter() {
+	t := suite.T()
+	form := NewRevealKeyForm([]string{"db.host", "db.password", "token"})
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("tok")})
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, RevealKeyMessage{Key: "token"}, cmd())
+	assert.NotContains(t, form.View(), "db.host")
+}
+
+func (suite *KeyPickerTestSuite) TestNoMatch() {
+	t := suite.T()
+	form := NewRevealKeyForm([]string{"token"})
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("zzz")})
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Nil(t, cmd)
+	assert.Contains(t, form.View(), "No matching key")
+}
+
+func (suite *KeyPickerTestSuite) TestCustomPick() {
+	t := suite.T()
+	form := NewKeyPicker("Copy key: ", "enter: copy", []string{"token"}, func(key string) tea.Msg {
+		return ShowToast{Text: "picked " + key}
+	})
+
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, ShowToast{Text: "picked token"}, cmd())
+	assert.Contains(t, form.View(), "Copy key: ")
+	assert.Contains(t, form.View(), "enter: copy")
+}
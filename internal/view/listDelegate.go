@@ -7,15 +7,23 @@ import (
 	"github.com/muesli/reflow/truncate"
 	"io"
 	"smm/internal/ui"
+	"strings"
+	"time"
 )
 
 type ItemDelegate struct {
-	Styles list.DefaultItemStyles
+	Styles       list.DefaultItemStyles
+	ShowProjects bool
+	// ExpiryWarning highlights secrets that expire sooner than it.
+	ExpiryWarning time.Duration
+	// Marked holds the MarkKey of the rows marked for export.
+	Marked map[string]bool
 }
 
 func NewListDelegate() *ItemDelegate {
 	return &ItemDelegate{
 		Styles: list.NewDefaultItemStyles(),
+		Marked: map[string]bool{},
 	}
 }
 
@@ -36,6 +44,23 @@ func (d *ItemDelegate) Render(w io.Writer, m list.Model, index int, item list.It
 			title = fmt.Sprintf("%s%s [v.%s]", ui.StyleLow().Render("├──"), item.(Secret).CreatedAt().Format("2006-01-02 15:04:05"), title)
 		}
 
+	} else if d.ShowProjects && item.(Secret).Project() != "" {
+		title = fmt.Sprintf("%s %s", ui.StyleLow().Render(item.(Secret).Project()+":"), title)
+	}
+	if location := item.(Secret).Location(); location != "" && item.(Secret).Type() == "current" {
+		title = fmt.Sprintf("%s %s", title, ui.StyleBadge().Render("["+location+"]"))
+	}
+	if item.(Secret).RotationDue() {
+		title = fmt.Sprintf("%s %s", title, ui.StyleWarning().Render("↻ due"))
+	}
+	if expireTime := item.(Secret).ExpireTime(); !expireTime.IsZero() && item.(Secret).Type() == "current" {
+		title = fmt.Sprintf("%s %s", title, d.renderExpiry(time.Until(expireTime)))
+	}
+	if aliases := item.(Secret).Aliases(); len(aliases) > 0 {
+		title = fmt.Sprintf("%s %s", title, ui.StyleTag().Render("@"+strings.Join(aliases, " @")))
+	}
+	if d.Marked[MarkKey(item.(Secret))] {
+		title = fmt.Sprintf("%s %s", ui.StyleTag().Render("●"), title)
 	}
 	textWidth := uint(m.Width() - s.NormalTitle.GetPaddingLeft() - s.NormalTitle.GetPaddingRight())
 	title = truncate.StringWithTail(title, textWidth, ellipsis)
@@ -51,6 +76,31 @@ func (d *ItemDelegate) Render(w io.Writer, m list.Model, index int, item list.It
 	_, _ = fmt.Fprintf(w, "%s", title)
 }
 
+// renderExpiry shows the remaining lifetime of a secret, highlighted inside
+// the warning window.
+func (d *ItemDelegate) renderExpiry(remaining time.Duration) string {
+	style := ui.StyleLow()
+	if remaining < d.ExpiryWarning {
+		style = ui.StyleWarning()
+	}
+	if remaining <= 0 {
+		return style.Render("⌛ expired")
+	}
+	return style.Render("⌛ " + formatTimeLeft(remaining))
+}
+
+// formatTimeLeft writes a remaining lifetime in its largest whole unit.
+func formatTimeLeft(remaining time.Duration) string {
+	switch {
+	case remaining >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(remaining.Hours()/24))
+	case remaining >= time.Hour:
+		return fmt.Sprintf("%dh", int(remaining.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(remaining.Minutes()))
+	}
+}
+
 func (d *ItemDelegate) Height() int {
 	return 1
 }
@@ -74,6 +74,81 @@ func (suite *ListDelegateTestSuite) TestRender_CurrentSecret() {
 	assert.Contains(t, result, "current-secret")
 }
 
+func (suite *ListDelegateTestSuite) TestRender_ShowProjects() {
+	t := suite.T()
+	secret := NewSecret("shared-secret", "projects/other/secrets/shared-secret", "current", 1, time.Now())
+	secret.SetProject("other")
+	suite.listModel.SetItems([]list.Item{secret})
+	var output strings.Builder
+
+	suite.delegate.ShowProjects = true
+	suite.delegate.Render(&output, suite.listModel, 0, secret)
+
+	assert.Contains(t, output.String(), "other:")
+	assert.Contains(t, output.String(), "shared-secret")
+}
+
+func (suite *ListDelegateTestSuite) TestRender_Aliases() {
+	t := suite.T()
+	versionSecret := NewSecret("3", "path", "version", 3, time.Now())
+	versionSecret.SetAliases([]string{"canary", "prod"})
+	suite.listModel.SetItems([]list.Item{versionSecret})
+	var output strings.Builder
+
+	suite.delegate.Render(&output, suite.listModel, 0, versionSecret)
+
+	assert.Contains(t, output.String(), "[v.3]")
+	assert.Contains(t, output.String(), "@canary @prod")
+}
+
+func (suite *ListDelegateTestSuite) TestRender_RotationDue() {
+	t := suite.T()
+	secret := NewSecret("api-key", "path", "current", 1, time.Now())
+	secret.SetRotationDue(true)
+	suite.listModel.SetItems([]list.Item{secret})
+	var output strings.Builder
+
+	suite.delegate.Render(&output, suite.listModel, 0, secret)
+
+	assert.Contains(t, output.String(), "↻ due")
+}
+
+func (suite *ListDelegateTestSuite) TestRender_Location() {
+	t := suite.T()
+	secret := NewSecret("eu-key", "projects/p/locations/europe-west1/secrets/eu-key", "current", 1, time.Now())
+	secret.SetLocation("europe-west1")
+	suite.listModel.SetItems([]list.Item{secret})
+	var output strings.Builder
+
+	suite.delegate.Render(&output, suite.listModel, 0, secret)
+
+	assert.Contains(t, output.String(), "[europe-west1]")
+}
+
+func (suite *ListDelegateTestSuite) TestRender_Expiry() {
+	t := suite.T()
+	secret := NewSecret("load-test", "path", "current", 1, time.Now())
+	secret.SetExpireTime(time.Now().Add(3*24*time.Hour + time.Minute))
+	suite.listModel.SetItems([]list.Item{secret})
+	var output strings.Builder
+
+	suite.delegate.Render(&output, suite.listModel, 0, secret)
+	assert.Contains(t, output.String(), "⌛ 3d")
+
+	secret.SetExpireTime(time.Now().Add(-time.Minute))
+	output.Reset()
+	suite.delegate.Render(&output, suite.listModel, 0, secret)
+	assert.Contains(t, output.String(), "⌛ expired")
+}
+
+func (suite *ListDelegateTestSuite) TestFormatTimeLeft() {
+	t := suite.T()
+
+	assert.Equal(t, "2d", formatTimeLeft(50*time.Hour))
+	assert.Equal(t, "5h", formatTimeLeft(5*time.Hour+10*time.Minute))
+	assert.Equal(t, "12m", formatTimeLeft(12*time.Minute))
+}
+
 func (suite *ListDelegateTestSuite) TestRender_VersionSecret_FirstVersion() {
 	t := suite.T()
 	versionSecret := NewSecret("1", "path", "version", 1, time.Now())
@@ -215,3 +290,15 @@ func (suite *ListDelegateTestSuite) TestRender_NarrowWidth() {
 	// Should be truncated due to narrow width
 	assert.Contains(t, result, "…")
 }
+
+func (suite *ListDelegateTestSuite) TestRender_Marked() {
+	t := suite.T()
+	secret := NewSecret("marked-secret", "path", "current", 0, time.Now())
+	suite.listModel.SetItems([]list.Item{secret})
+	var output strings.Builder
+
+	suite.delegate.Marked[MarkKey(secret)] = true
+	suite.delegate.Render(&output, suite.listModel, 0, secret)
+
+	assert.Contains(t, output.String(), "● marked-secret")
+}
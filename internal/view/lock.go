@@ -0,0 +1,23 @@
+package view
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"smm/internal/ui"
+)
+
+// LockScreen replaces the whole interface while smm is locked, saying why it
+// locked and, when the last unlock failed, the reason.
+func LockScreen(width, height int, reason, alert string) string {
+	lines := []string{
+		ui.StyleWarning().Render("🔒 smm is locked"),
+		ui.StyleLow().Render(reason),
+		"",
+		"Press any key to resume",
+	}
+	if alert != "" {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true).Render(alert))
+	}
+
+	box := ui.StyleModal().Render(lipgloss.JoinVertical(lipgloss.Center, lines...))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,36 @@
+package view
+
+import (
+	"testing"
+
+	"github.com/acarl005/stripansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LockTestSuite struct {
+	suite.Suite
+}
+
+func TestLockSuite(t *testing.T) {
+	suite.Run(t, new(LockTestSuite))
+}
+
+func (suite *LockTestSuite) TestLockScreen() {
+	t := suite.T()
+
+	screen := stripansi.Strip(LockScreen(80, 24, "idle for 10m0s", ""))
+
+	assert.Contains(t, screen, "smm is locked")
+	assert.Contains(t, screen, "idle for 10m0s")
+	assert.Contains(t, screen, "Press any key to resume")
+	assert.NotContains(t, screen, "failed")
+}
+
+func (suite *LockTestSuite) TestLockScreenWithAlert() {
+	t := suite.T()
+
+	screen := stripansi.Strip(LockScreen(80, 24, "terminal lost focus", "Re-authentication failed"))
+
+	assert.Contains(t, screen, "Re-authentication failed")
+}
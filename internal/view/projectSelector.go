@@ -1,41 +1,92 @@
 package view
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+	"smm/internal/client"
 	"smm/internal/config"
+	"smm/internal/ui"
 )
 
+// maxProjectRows is the number of projects listed at once.
+const maxProjectRows = 10
+
+// ProjectSelectedMessage switches to ProjectId and, when SecretName is set,
+// selects that secret once the project is loaded.
 type ProjectSelectedMessage struct {
-	ProjectId string
+	ProjectId  string
+	SecretName string
 }
 
 type ShowProjectSelectMsg struct {
 	TextAlert string
 }
 
+// ProjectsDiscoveredMessage carries the projects found by a discoverer.
+type ProjectsDiscoveredMessage struct {
+	Projects []client.DiscoveredProject
+	Err      error
+}
+
+// projectRow is a configured project or, when discovered, one that is
+// added on selection.
+type projectRow struct {
+	project    config.Project
+	discovered bool
+}
+
+// projectRows is the fuzzy.Source of the rows, matching ID, name and
+// environment.
+type projectRows []projectRow
+
+func (r projectRows) String(i int) string {
+	return r[i].project.ID + " " + r[i].project.Name + " " + r[i].project.Env
+}
+
+func (r projectRows) Len() int {
+	return len(r)
+}
+
+// ProjectSelector lists the configured projects, most recently used first,
+// and the discovered ones, filtered fuzzily by the input.
 type ProjectSelector struct {
-	teaView    textinput.Model
-	alertText  string
-	alertStyle lipgloss.Style
+	teaView     textinput.Model
+	discoverer  client.ProjectDiscoverer
+	discovered  []client.DiscoveredProject
+	discovering bool
+	rows        projectRows
+	cursor      int
+	editing     bool
+	editID      string
+	editFields  []textinput.Model
+	editFocus   int
+	removeID    string
+	alertText   string
+	alertStyle  lipgloss.Style
 }
 
-func NewProjectSelectorModal() *ProjectSelector {
+func NewProjectSelectorModal(discoverer client.ProjectDiscoverer) *ProjectSelector {
 	projectId := textinput.New()
-	projectId.Prompt = "Project ID: "
-	projectId.ShowSuggestions = true
-	projectId.SetSuggestions(config.GetProjectIDs())
-	projectId.Placeholder = ""
+	projectId.Prompt = "Project: "
+	projectId.Placeholder = "filter or type a project ID"
 	projectId.Focus()
 	projectId.CharLimit = 128
-	projectId.Width = 32
+	projectId.Width = 48
 
 	alertStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#FF6B6B")).
 		Bold(true)
 
-	return &ProjectSelector{teaView: projectId, alertStyle: alertStyle}
+	p := &ProjectSelector{teaView: projectId, discoverer: discoverer, alertStyle: alertStyle}
+	p.refresh()
+	return p
 }
 
 func (p *ProjectSelector) Init() tea.Cmd {
@@ -46,28 +97,248 @@ func (p *ProjectSelector) Value() string {
 	return p.teaView.Value()
 }
 
+// refresh lists the projects again, keeping the cursor within the rows.
+func (p *ProjectSelector) refresh() {
+	var all projectRows
+	configured := map[string]bool{}
+	projects := config.GetProjects()
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].LastUsedTime().After(projects[j].LastUsedTime())
+	})
+	for _, project := range projects {
+		configured[project.ID] = true
+		all = append(all, projectRow{project: project})
+	}
+	for _, project := range p.discovered {
+		if !configured[project.ID] {
+			all = append(all, projectRow{project: config.Project{ID: project.ID, Name: project.Name, Type: project.Type}, discovered: true})
+		}
+	}
+
+	p.rows = all
+	if pattern := strings.TrimSpace(p.teaView.Value()); pattern != "" {
+		p.rows = nil
+		for _, match := range fuzzy.FindFrom(pattern, all) {
+			p.rows = append(p.rows, all[match.Index])
+		}
+	}
+	p.cursor = min(p.cursor, max(len(p.rows)-1, 0))
+}
+
+func (p *ProjectSelector) selected() (projectRow, bool) {
+	if len(p.rows) == 0 {
+		return projectRow{}, false
+	}
+	return p.rows[p.cursor], true
+}
+
 func (p *ProjectSelector) Update(msg tea.Msg) (Modal, tea.Cmd) {
-	var cmd tea.Cmd
-	var cmds []tea.Cmd
 	switch msg := msg.(type) {
+	case ProjectsDiscoveredMessage:
+		p.discovering = false
+		if msg.Err != nil {
+			p.alertText = "Discovery failed: " + msg.Err.Error()
+			return p, nil
+		}
+		p.discovered = msg.Projects
+		p.alertText = fmt.Sprintf("Discovered %d projects", len(msg.Projects))
+		p.refresh()
+		return p, nil
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
-
-			config.SetSelectedProject(p.teaView.Value())
-			config.AddProjectID(p.teaView.Value())
-			_ = config.Save()
+		if p.editing {
+			return p.updateEdit(msg)
+		}
+		if p.removeID != "" {
+			p.confirmRemove(msg.String() == "y")
+			return p, nil
+		}
 
-			cmd = func() tea.Msg {
-				return ProjectSelectedMessage{p.teaView.Value()}
+		switch msg.String() {
+		case "up", "ctrl+p":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+			return p, nil
+		case "down", "ctrl+n":
+			if p.cursor < len(p.rows)-1 {
+				p.cursor++
 			}
-			cmds = append(cmds, cmd)
+			return p, nil
+		case "enter":
+			return p, p.selectProject()
+		case "ctrl+d":
+			p.removeProject()
+			return p, nil
+		case "ctrl+e":
+			p.startEdit()
+			return p, nil
+		case "ctrl+s":
+			p.toggleDefault()
+			return p, nil
+		case "ctrl+r":
+			return p, p.discover()
 		}
-
 	}
+
+	var cmd tea.Cmd
+	previous := p.teaView.Value()
 	p.teaView, cmd = p.teaView.Update(msg)
-	cmds = append(cmds, cmd)
-	return p, tea.Batch(cmds...)
+	if p.teaView.Value() != previous {
+		p.alertText = ""
+		p.cursor = 0
+		p.refresh()
+	}
+	return p, cmd
+}
+
+// selectProject opens the selected project, adding it to the config when it
+// was discovered. Without matches, the input is taken as a new project ID.
+func (p *ProjectSelector) selectProject() tea.Cmd {
+	row, ok := p.selected()
+	if !ok {
+		projectId := strings.TrimSpace(p.teaView.Value())
+		if err := client.ValidateProjectID(projectId); err != nil {
+			p.alertText = err.Error()
+			return nil
+		}
+		row = projectRow{project: config.Project{ID: projectId, Type: "gcp"}, discovered: true}
+	}
+
+	if row.discovered {
+		config.AddProject(row.project)
+	}
+	config.SetSelectedProject(row.project.ID)
+	_ = config.Save()
+
+	return func() tea.Msg {
+		return ProjectSelectedMessage{ProjectId: row.project.ID}
+	}
+}
+
+// removeProject asks to confirm the removal of the selected project, which
+// the next key answers.
+func (p *ProjectSelector) removeProject() {
+	row, ok := p.selected()
+	if !ok || row.discovered {
+		p.alertText = "Only configured projects can be removed"
+		return
+	}
+
+	p.removeID = row.project.ID
+	p.alertText = "Remove " + row.project.ID + " from the config? y/n"
+}
+
+func (p *ProjectSelector) confirmRemove(confirmed bool) {
+	projectId := p.removeID
+	p.removeID = ""
+	if !confirmed {
+		p.alertText = "Kept " + projectId
+		return
+	}
+
+	config.RemoveProject(projectId)
+	_ = config.Save()
+	p.alertText = "Removed " + projectId
+	p.refresh()
+}
+
+func (p *ProjectSelector) toggleDefault() {
+	row, ok := p.selected()
+	if !ok || row.discovered {
+		p.alertText = "Open the project once before making it the default"
+		return
+	}
+
+	if config.GetDefaultProjectId() == row.project.ID {
+		config.SetDefaultProjectId("")
+		p.alertText = "No default project"
+	} else {
+		config.SetDefaultProjectId(row.project.ID)
+		p.alertText = row.project.ID + " opens on start"
+	}
+	_ = config.Save()
+}
+
+func (p *ProjectSelector) discover() tea.Cmd {
+	if p.discovering || p.discoverer == nil {
+		return nil
+	}
+	p.discovering = true
+	p.alertText = "Discovering projects..."
+
+	discoverer := p.discoverer
+	return func() tea.Msg {
+		projects, err := discoverer.DiscoverProjects()
+		return ProjectsDiscoveredMessage{Projects: projects, Err: err}
+	}
+}
+
+// startEdit opens the form editing the name, environment, colour and type
+// of the selected project.
+func (p *ProjectSelector) startEdit() {
+	row, ok := p.selected()
+	if !ok || row.discovered {
+		p.alertText = "Only configured projects can be edited"
+		return
+	}
+
+	values := []struct{ prompt, value string }{
+		{"Name:  ", row.project.Name},
+		{"Env:   ", row.project.Env},
+		{"Color: ", row.project.Color},
+		{"Type:  ", row.project.Type},
+	}
+	p.editFields = make([]textinput.Model, len(values))
+	for i, field := range values {
+		input := textinput.New()
+		input.Prompt = field.prompt
+		input.SetValue(field.value)
+		input.CharLimit = 64
+		input.Width = 40
+		p.editFields[i] = input
+	}
+	p.editFields[0].Focus()
+	p.editFocus = 0
+	p.editID = row.project.ID
+	p.editing = true
+	p.alertText = ""
+}
+
+func (p *ProjectSelector) updateEdit(msg tea.KeyMsg) (Modal, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyTab, tea.KeyShiftTab:
+		p.editFields[p.editFocus].Blur()
+		step := 1
+		if msg.Type == tea.KeyShiftTab {
+			step = len(p.editFields) - 1
+		}
+		p.editFocus = (p.editFocus + step) % len(p.editFields)
+		p.editFields[p.editFocus].Focus()
+		return p, nil
+	case tea.KeyEnter:
+		projectType := strings.TrimSpace(p.editFields[3].Value())
+		if err := client.ValidateProjectType(projectType); err != nil {
+			p.alertText = err.Error()
+			return p, nil
+		}
+
+		project := config.GetProject(p.editID)
+		project.Name = strings.TrimSpace(p.editFields[0].Value())
+		project.Env = strings.TrimSpace(p.editFields[1].Value())
+		project.Color = strings.TrimSpace(p.editFields[2].Value())
+		project.Type = projectType
+		config.UpdateProject(project)
+		_ = config.Save()
+
+		p.editing = false
+		p.alertText = "Saved " + p.editID
+		p.refresh()
+		return p, nil
+	}
+
+	var cmd tea.Cmd
+	p.editFields[p.editFocus], cmd = p.editFields[p.editFocus].Update(msg)
+	return p, cmd
 }
 
 func (p *ProjectSelector) SetAlert(text string) {
@@ -78,16 +349,79 @@ func (p *ProjectSelector) ClearAlert() {
 	p.alertText = ""
 }
 
+// rowView renders a project with its name, environment, type, last use and
+// whether it is the default.
+func (p *ProjectSelector) rowView(row projectRow, selected bool) string {
+	project := row.project
+	parts := []string{project.ID}
+	if project.Name != "" {
+		parts = append(parts, ui.StyleLow().Render(project.Name))
+	}
+	if project.Env != "" {
+		env := lipgloss.NewStyle().Bold(true).Foreground(ui.AccentColor(project.Color))
+		parts = append(parts, env.Render("["+project.Env+"]"))
+	}
+	if row.discovered {
+		parts = append(parts, ui.StyleBadge().Render("discovered"))
+	} else {
+		parts = append(parts, ui.StyleTag().Render(project.Type), ui.StyleLow().Render(formatAgo(project.LastUsedTime(), time.Now())))
+	}
+	if project.ID == config.GetDefaultProjectId() {
+		parts = append(parts, "★")
+	}
+
+	line := strings.Join(parts, " ")
+	if selected {
+		return "› " + line
+	}
+	return "  " + line
+}
+
 func (p *ProjectSelector) View() string {
-	view := p.teaView.View()
+	styles := newSecretInfoStyles()
+	if p.editing {
+		sections := []string{styles.title.Render("Edit " + p.editID)}
+		for _, field := range p.editFields {
+			sections = append(sections, field.View())
+		}
+		sections = append(sections, "", styles.footer.Render("tab: next field · enter: save · esc: close"))
+		if p.alertText != "" {
+			sections = append(sections, p.alertStyle.Render(p.alertText))
+		}
+		return lipgloss.NewStyle().Width(64).Render(strings.Join(sections, "\n"))
+	}
 
+	sections := []string{p.teaView.View(), ""}
+	if len(p.rows) == 0 {
+		sections = append(sections, styles.value.Render("No matching project, enter adds it"))
+	}
+	start := max(0, min(p.cursor-maxProjectRows/2, len(p.rows)-maxProjectRows))
+	for i := start; i < len(p.rows) && i < start+maxProjectRows; i++ {
+		sections = append(sections, p.rowView(p.rows[i], i == p.cursor))
+	}
+
+	sections = append(sections, "", styles.footer.Render("enter: open · ctrl+e: edit · ctrl+d: remove · ctrl+s: default · ctrl+r: discover"))
 	if p.alertText != "" {
-		alertView := p.alertStyle.
-			Width(32).
-			Align(lipgloss.Center).
-			Render(p.alertText)
-		view = lipgloss.JoinVertical(lipgloss.Left, view, alertView)
+		sections = append(sections, p.alertStyle.Render(p.alertText))
 	}
+	return lipgloss.NewStyle().Width(64).Render(strings.Join(sections, "\n"))
+}
 
-	return lipgloss.NewStyle().Width(34).Render(view)
+// formatAgo describes how long before now t was, or "never" for the zero
+// time.
+func formatAgo(t, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	elapsed := now.Sub(t)
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(elapsed.Hours()/24))
+	}
 }
@@ -0,0 +1,163 @@
+package view
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"smm/internal/client"
+	"smm/internal/config"
+)
+
+type ProjectSelectorTestSuite struct {
+	suite.Suite
+	selector *ProjectSelector
+}
+
+func (suite *ProjectSelectorTestSuite) SetupTest() {
+	viper.Reset()
+	viper.Set("projects", []config.Project{
+		{ID: "payments-dev", Type: "gcp", Name: "Payments", Env: "dev", LastUsed: "2025-01-01T00:00:00Z"},
+		{ID: "payments-prod", Type: "gcp", Name: "Payments", Env: "prod", Color: "red", LastUsed: "2025-03-01T00:00:00Z"},
+		{ID: "sandbox-fake", Type: "fake"},
+	})
+	suite.selector = NewProjectSelectorModal(client.FakeProjects{})
+}
+
+func (suite *ProjectSelectorTestSuite) TearDownTest() {
+	viper.Reset()
+}
+
+func TestProjectSelectorSuite(t *testing.T) {
+	suite.Run(t, new(ProjectSelectorTestSuite))
+}
+
+func (suite *ProjectSelectorTestSuite) typeText(text string) {
+	for _, r := range text {
+		suite.selector.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func (suite *ProjectSelectorTestSuite) TestListsMostRecentlyUsedFirst() {
+	t := suite.T()
+
+	assert.Len(t, suite.selector.rows, 3)
+	assert.Equal(t, "payments-prod", suite.selector.rows[0].project.ID)
+	assert.Equal(t, "sandbox-fake", suite.selector.rows[2].project.ID)
+
+	view := suite.selector.View()
+	assert.Contains(t, view, "[prod]")
+	assert.Contains(t, view, "never")
+}
+
+func (suite *ProjectSelectorTestSuite) TestFuzzyFilter() {
+	t := suite.T()
+
+	suite.typeText("pydv")
+
+	assert.Len(t, suite.selector.rows, 1)
+	assert.Equal(t, "payments-dev", suite.selector.rows[0].project.ID)
+}
+
+func (suite *ProjectSelectorTestSuite) TestEnterOpensSelected() {
+	t := suite.T()
+
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := suite.selector.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.NotNil(t, cmd)
+	assert.Equal(t, ProjectSelectedMessage{ProjectId: "payments-dev"}, cmd())
+}
+
+func (suite *ProjectSelectorTestSuite) TestEnterRejectsInvalidID() {
+	t := suite.T()
+
+	suite.typeText("Bad_ID")
+	_, cmd := suite.selector.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Nil(t, cmd)
+	assert.Contains(t, suite.selector.View(), "is not a project ID")
+	assert.Len(t, config.GetProjects(), 3)
+}
+
+func (suite *ProjectSelectorTestSuite) TestDiscoverAndAdd() {
+	t := suite.T()
+
+	_, cmd := suite.selector.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	assert.NotNil(t, cmd)
+	suite.selector.Update(cmd())
+	assert.Contains(t, suite.selector.View(), "discovered")
+
+	suite.typeText("platform")
+	_, cmd = suite.selector.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, ProjectSelectedMessage{ProjectId: "fake-platform-staging"}, cmd())
+	added := config.GetProject("fake-platform-staging")
+	assert.Equal(t, "fake", added.Type)
+	assert.Equal(t, "Platform staging", added.Name)
+}
+
+func (suite *ProjectSelectorTestSuite) TestRemoveAndDefault() {
+	t := suite.T()
+
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	assert.Equal(t, "payments-prod", config.GetDefaultProjectId())
+	assert.Contains(t, suite.selector.View(), "★")
+
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	assert.Contains(t, suite.selector.View(), "Remove payments-prod")
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	assert.Len(t, config.GetProjects(), 3)
+	assert.Equal(t, "payments-prod", config.GetDefaultProjectId())
+
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	assert.Len(t, config.GetProjects(), 2)
+	assert.Empty(t, config.GetDefaultProjectId())
+	assert.Len(t, suite.selector.rows, 2)
+}
+
+func (suite *ProjectSelectorTestSuite) TestEdit() {
+	t := suite.T()
+
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	assert.True(t, suite.selector.editing)
+
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyTab})
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	suite.typeText("production")
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.False(t, suite.selector.editing)
+	assert.Equal(t, "production", config.GetProject("payments-prod").Env)
+	assert.Equal(t, "Payments", config.GetProject("payments-prod").Name)
+}
+
+func (suite *ProjectSelectorTestSuite) TestEditRejectsUnknownType() {
+	t := suite.T()
+	before := config.GetProject("payments-prod").Type
+
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	suite.selector.editFields[3].SetValue("aws")
+	suite.selector.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.True(t, suite.selector.editing)
+	assert.Contains(t, suite.selector.View(), `unknown type "aws"`)
+	assert.Equal(t, before, config.GetProject("payments-prod").Type)
+}
+
+func TestFormatAgo(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "never", formatAgo(time.Time{}, now))
+	assert.Equal(t, "just now", formatAgo(now.Add(-10*time.Second), now))
+	assert.Equal(t, "5m ago", formatAgo(now.Add(-5*time.Minute), now))
+	assert.Equal(t, "3h ago", formatAgo(now.Add(-3*time.Hour), now))
+	assert.Equal(t, "9d ago", formatAgo(now.Add(-9*24*time.Hour), now))
+}
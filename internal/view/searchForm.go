@@ -5,26 +5,40 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/rs/zerolog/log"
+	"smm/internal/search"
+	"smm/internal/ui"
 )
 
 type SearchMessage struct {
-	Query string
+	Query       string
+	Parsed      *search.Query
+	AllVersions bool
+	AllProjects bool
 }
 
 type SearchForm struct {
-	teaView textinput.Model
+	teaView     textinput.Model
+	ignoreCase  bool
+	allVersions bool
+	allProjects bool
+	alertText   string
+	alertStyle  lipgloss.Style
 }
 
 func NewSearchForm() *SearchForm {
 	form := textinput.New()
 	form.Prompt = "Query search: "
 	form.ShowSuggestions = false
-	form.Placeholder = ""
+	form.Placeholder = "key:NAME value:/re/ label:k=v created:<30d"
 	form.Focus()
-	form.CharLimit = 128
-	form.Width = 32
+	form.CharLimit = 256
+	form.Width = 48
 
-	return &SearchForm{teaView: form}
+	alertStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF6B6B")).
+		Bold(true)
+
+	return &SearchForm{teaView: form, alertStyle: alertStyle}
 }
 
 func (p *SearchForm) Init() tea.Cmd {
@@ -35,18 +49,56 @@ func (p *SearchForm) Value() string {
 	return p.teaView.Value()
 }
 
+func (p *SearchForm) IgnoreCase() bool {
+	return p.ignoreCase
+}
+
+func (p *SearchForm) AllVersions() bool {
+	return p.allVersions
+}
+
+func (p *SearchForm) AllProjects() bool {
+	return p.allProjects
+}
+
 func (p *SearchForm) Update(msg tea.Msg) (Modal, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+t":
+			p.ignoreCase = !p.ignoreCase
+			return p, nil
+		case "ctrl+o":
+			p.allVersions = !p.allVersions
+			return p, nil
+		case "ctrl+g":
+			p.allProjects = !p.allProjects
+			return p, nil
+		}
+
 		switch msg.Type {
 		case tea.KeyEnter:
 			log.Info().Msgf("SearchForm: %v", p.teaView.Value())
+			query := p.teaView.Value()
+
+			var parsed *search.Query
+			if query != "" {
+				var err error
+				parsed, err = search.Parse(query, search.Options{IgnoreCase: p.ignoreCase})
+				if err != nil {
+					p.alertText = err.Error()
+					return p, nil
+				}
+			}
+
 			cmd = func() tea.Msg {
-				return SearchMessage{p.teaView.Value()}
+				return SearchMessage{Query: query, Parsed: parsed, AllVersions: p.allVersions, AllProjects: p.allProjects}
 			}
 			cmds = append(cmds, cmd)
+		default:
+			p.alertText = ""
 		}
 
 	}
@@ -56,5 +108,25 @@ func (p *SearchForm) Update(msg tea.Msg) (Modal, tea.Cmd) {
 }
 
 func (p *SearchForm) View() string {
-	return lipgloss.NewStyle().Width(34).Height(1).Render(p.teaView.View())
+	caseText := "case-sensitive"
+	if p.ignoreCase {
+		caseText = "ignore case"
+	}
+	versionsText := "latest version"
+	if p.allVersions {
+		versionsText = "all enabled versions"
+	}
+	projectsText := "current project"
+	if p.allProjects {
+		projectsText = "all projects"
+	}
+	options := ui.StyleLow().Render("ctrl+t: " + caseText + " · ctrl+o: " + versionsText + " · ctrl+g: " + projectsText)
+	syntax := ui.StyleLow().Render("AND, OR and ( ) combine terms, project:a,b adds projects")
+
+	view := lipgloss.JoinVertical(lipgloss.Left, p.teaView.View(), options, syntax)
+	if p.alertText != "" {
+		view = lipgloss.JoinVertical(lipgloss.Left, view, p.alertStyle.Render(p.alertText))
+	}
+
+	return lipgloss.NewStyle().Width(80).Render(view)
 }
@@ -73,6 +73,71 @@ func (suite *SearchFormTestSuite) TestUpdate_EnterKey() {
 	assert.Equal(t, "test", searchMsg.Query)
 }
 
+func (suite *SearchFormTestSuite) TestUpdate_EnterKey_ParsesQuery() {
+	t := suite.T()
+	for _, r := range "key:REDIS_URL" {
+		suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	_, cmd := suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	searchMsg, ok := cmd().(SearchMessage)
+	assert.True(t, ok)
+	assert.NotNil(t, searchMsg.Parsed)
+	assert.Equal(t, "key:REDIS_URL", searchMsg.Parsed.String())
+}
+
+func (suite *SearchFormTestSuite) TestUpdate_EnterKey_InvalidQuery() {
+	t := suite.T()
+	for _, r := range "created:soon" {
+		suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	_, cmd := suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Nil(t, cmd)
+	assert.Contains(t, suite.searchForm.View(), "created:")
+}
+
+func (suite *SearchFormTestSuite) TestUpdate_ToggleIgnoreCase() {
+	t := suite.T()
+
+	suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	assert.True(t, suite.searchForm.IgnoreCase())
+	assert.Contains(t, suite.searchForm.View(), "ignore case")
+
+	suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	assert.False(t, suite.searchForm.IgnoreCase())
+}
+
+func (suite *SearchFormTestSuite) TestUpdate_ToggleAllVersions() {
+	t := suite.T()
+	suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+
+	suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	assert.True(t, suite.searchForm.AllVersions())
+	assert.Contains(t, suite.searchForm.View(), "all enabled versions")
+
+	_, cmd := suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	searchMsg, ok := cmd().(SearchMessage)
+	assert.True(t, ok)
+	assert.True(t, searchMsg.AllVersions)
+}
+
+func (suite *SearchFormTestSuite) TestUpdate_ToggleAllProjects() {
+	t := suite.T()
+	suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+
+	suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	assert.True(t, suite.searchForm.AllProjects())
+	assert.Contains(t, suite.searchForm.View(), "all projects")
+
+	_, cmd := suite.searchForm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	searchMsg, ok := cmd().(SearchMessage)
+	assert.True(t, ok)
+	assert.True(t, searchMsg.AllProjects)
+}
+
 func (suite *SearchFormTestSuite) TestUpdate_RegularKey() {
 	t := suite.T()
 	keyMsg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}
@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"smm/internal/client"
+	"smm/internal/ui"
 )
 
 // Styles for the SecretInfoModal
@@ -39,6 +40,7 @@ func newSecretInfoStyles() secretInfoStyles {
 type SecretInfoModal struct {
 	secretInfo   client.SecretInfo
 	selectedItem Secret
+	versions     []client.Version
 	width        int
 	height       int
 }
@@ -56,6 +58,12 @@ func NewSecretInfoModal(secretInfo client.SecretInfo, selectedItem Secret) *Secr
 	}
 }
 
+// SetVersions adds the version count, the latest enabled version and the
+// replication status of the selected version to the modal.
+func (s *SecretInfoModal) SetVersions(versions []client.Version) {
+	s.versions = versions
+}
+
 func (s *SecretInfoModal) Init() tea.Cmd {
 	return nil
 }
@@ -85,6 +93,9 @@ func (s *SecretInfoModal) View() string {
 	sections = append(sections, "")
 	sections = append(sections, s.buildSecretInfoSection(styles)...)
 
+	sections = append(sections, "")
+	sections = append(sections, s.buildMetadataSection(styles)...)
+
 	// Build version info section only for version items, not current
 	if s.selectedItem.Type() == "version" {
 		sections = append(sections, "")
@@ -98,6 +109,45 @@ func (s *SecretInfoModal) View() string {
 	return strings.Join(sections, "\n")
 }
 
+// Summary renders the secret information and its versions for the detail
+// panel, where the value is only shown once requested with key.
+func (s *SecretInfoModal) Summary(key string) string {
+	styles := newSecretInfoStyles()
+	sections := s.buildSecretInfoSection(styles)
+
+	if s.selectedItem.Type() == "version" {
+		sections = append(sections, "")
+		sections = append(sections, s.buildVersionInfoSection(styles)...)
+	}
+	if len(s.versions) > 0 {
+		sections = append(sections, "")
+		sections = append(sections, s.buildVersionsSection(styles)...)
+	}
+
+	sections = append(sections, "", styles.footer.Render("Press "+key+" to fetch the value"))
+	return strings.Join(sections, "\n")
+}
+
+// buildVersionsSection lists the most recent versions with their state,
+// creation time and aliases
+func (s *SecretInfoModal) buildVersionsSection(styles secretInfoStyles) []string {
+	const maxVersions = 10
+
+	sections := []string{styles.label.Render(fmt.Sprintf("Versions (%d):", len(s.versions)))}
+	for i, version := range s.versions {
+		if i == maxVersions {
+			sections = append(sections, styles.value.Render(fmt.Sprintf("    … %d more", len(s.versions)-maxVersions)))
+			break
+		}
+		entry := fmt.Sprintf("    %-4d %-9s %s", version.Version, strings.ToLower(version.State), version.CreatedAt.UTC().Format("2006-01-02 15:04"))
+		for _, alias := range version.Aliases {
+			entry += " @" + alias
+		}
+		sections = append(sections, styles.value.Render(entry))
+	}
+	return sections
+}
+
 // buildTitle creates the modal title based on the selected item type
 func (s *SecretInfoModal) buildTitle(styles secretInfoStyles) string {
 	var title string
@@ -133,6 +183,100 @@ func (s *SecretInfoModal) buildSecretInfoSection(styles secretInfoStyles) []stri
 		sections = append(sections, s.buildMapSection(styles, "  Annotations:", s.secretInfo.Annotations, true)...)
 	}
 
+	sections = append(sections, s.buildRotationSection(styles)...)
+
+	expires := "never"
+	if !s.secretInfo.ExpireTime.IsZero() {
+		expires = s.secretInfo.ExpireTime.UTC().Format("2006-01-02 15:04:05 UTC")
+		if remaining := time.Until(s.secretInfo.ExpireTime); remaining > 0 {
+			expires += " (in " + formatTimeLeft(remaining) + ")"
+		} else {
+			expires += " " + ui.StyleWarning().Render("(expired)")
+		}
+	}
+	sections = append(sections, s.buildFieldRow(styles, "  Expires: ", expires))
+
+	return sections
+}
+
+// buildRotationSection shows the rotation schedule and notification topics
+func (s *SecretInfoModal) buildRotationSection(styles secretInfoStyles) []string {
+	var sections []string
+
+	if s.secretInfo.NextRotationTime.IsZero() {
+		sections = append(sections, s.buildFieldRow(styles, "  Rotation: ", "not configured"))
+	} else {
+		next := s.secretInfo.NextRotationTime.UTC().Format("2006-01-02 15:04:05 UTC")
+		if s.secretInfo.RotationDue(time.Now()) {
+			next += " " + ui.StyleWarning().Render("(due)")
+		}
+		sections = append(sections, s.buildFieldRow(styles, "  Next Rotation: ", next))
+
+		period := "single rotation"
+		if s.secretInfo.RotationPeriod > 0 {
+			period = "every " + client.FormatPeriod(s.secretInfo.RotationPeriod)
+		}
+		sections = append(sections, s.buildFieldRow(styles, "  Rotation Period: ", period))
+	}
+
+	if len(s.secretInfo.Topics) == 0 {
+		sections = append(sections, s.buildFieldRow(styles, "  Topics: ", "none"))
+	} else {
+		sections = append(sections, styles.label.Render("  Topics:"))
+		for _, topic := range s.secretInfo.Topics {
+			sections = append(sections, styles.value.Render("    "+topic))
+		}
+	}
+
+	return sections
+}
+
+// buildMetadataSection shows where and how the secret is stored
+func (s *SecretInfoModal) buildMetadataSection(styles secretInfoStyles) []string {
+	var sections []string
+
+	sections = append(sections, styles.label.Render("Metadata:"))
+
+	replication := s.secretInfo.Replication
+	if replication == "" {
+		replication = "unknown"
+	} else if len(s.secretInfo.Locations) > 0 {
+		replication += " (" + strings.Join(s.secretInfo.Locations, ", ") + ")"
+	}
+	sections = append(sections, s.buildFieldRow(styles, "  Replication: ", replication))
+
+	if len(s.secretInfo.KmsKeys) == 0 {
+		sections = append(sections, s.buildFieldRow(styles, "  Encryption: ", "Google-managed"))
+	} else {
+		sections = append(sections, styles.label.Render("  Encryption (CMEK):"))
+		for _, key := range s.secretInfo.KmsKeys {
+			sections = append(sections, styles.value.Render("    "+key))
+		}
+	}
+
+	if s.secretInfo.Etag != "" {
+		sections = append(sections, s.buildFieldRow(styles, "  Etag: ", s.secretInfo.Etag))
+	}
+
+	if s.versions != nil {
+		latest := "none enabled"
+		if version := client.LatestEnabled(s.versions); version > 0 {
+			latest = fmt.Sprintf("latest enabled %d", version)
+		}
+		sections = append(sections, s.buildFieldRow(styles, "  Versions: ", fmt.Sprintf("%d (%s)", len(s.versions), latest)))
+	}
+
+	destroyTTL := "none, destroyed immediately"
+	if s.secretInfo.VersionDestroyTTL > 0 {
+		destroyTTL = client.FormatPeriod(s.secretInfo.VersionDestroyTTL)
+	}
+	sections = append(sections, s.buildFieldRow(styles, "  Version Destroy TTL: ", destroyTTL))
+
+	if s.secretInfo.Name != "" && s.secretInfo.Project != "" {
+		link := ui.Hyperlink(s.secretInfo.ConsoleURL(), "open in Cloud Console")
+		sections = append(sections, styles.label.Render("  Console: ")+link)
+	}
+
 	return sections
 }
 
@@ -156,6 +300,33 @@ func (s *SecretInfoModal) buildVersionInfoSection(styles secretInfoStyles) []str
 	versionAgeStr := formatTimeAge(time.Since(s.selectedItem.CreatedAt()))
 	sections = append(sections, s.buildFieldRow(styles, "  Age: ", versionAgeStr))
 
+	for _, version := range s.versions {
+		if version.Version == s.selectedItem.Version() {
+			sections = append(sections, s.buildReplicaRows(styles, version.Replicas)...)
+		}
+	}
+
+	return sections
+}
+
+// buildReplicaRows shows the replication status of a version
+func (s *SecretInfoModal) buildReplicaRows(styles secretInfoStyles, replicas []client.Replica) []string {
+	if len(replicas) == 1 && replicas[0].Location == "" {
+		status := "automatic"
+		if replicas[0].KmsKeyVersion != "" {
+			status += ", " + replicas[0].KmsKeyVersion
+		}
+		return []string{s.buildFieldRow(styles, "  Replication: ", status)}
+	}
+
+	sections := []string{styles.label.Render("  Replicas:")}
+	for _, replica := range replicas {
+		entry := "    " + replica.Location
+		if replica.KmsKeyVersion != "" {
+			entry += ": " + replica.KmsKeyVersion
+		}
+		sections = append(sections, styles.value.Render(entry))
+	}
 	return sections
 }
 
@@ -172,3 +172,94 @@ func TestSecretInfoModalImplementsModal(t *testing.T) {
 	// Ensure it implements the Modal interface
 	var _ Modal = modal
 }
+
+func (suite *SecretInfoModalTestSuite) TestViewRotation() {
+	t := suite.T()
+
+	view := suite.modal.View()
+	assert.Contains(t, view, "Rotation: not configured")
+	assert.Contains(t, view, "Topics: none")
+
+	secretInfo := suite.secretInfo
+	secretInfo.NextRotationTime = time.Now().Add(-time.Hour)
+	secretInfo.RotationPeriod = 30 * 24 * time.Hour
+	secretInfo.Topics = []string{"projects/test-project/topics/rotation"}
+	view = NewSecretInfoModal(secretInfo, suite.testSecret).View()
+
+	assert.Contains(t, view, "Next Rotation:")
+	assert.Contains(t, view, "(due)")
+	assert.Contains(t, view, "every 30d")
+	assert.Contains(t, view, "projects/test-project/topics/rotation")
+}
+
+func (suite *SecretInfoModalTestSuite) TestViewExpiration() {
+	t := suite.T()
+
+	assert.Contains(t, suite.modal.View(), "Expires: never")
+
+	secretInfo := suite.secretInfo
+	secretInfo.ExpireTime = time.Now().Add(26 * time.Hour)
+	assert.Contains(t, NewSecretInfoModal(secretInfo, suite.testSecret).View(), "(in 1d)")
+
+	secretInfo.ExpireTime = time.Now().Add(-time.Hour)
+	assert.Contains(t, NewSecretInfoModal(secretInfo, suite.testSecret).View(), "(expired)")
+}
+
+func (suite *SecretInfoModalTestSuite) TestViewMetadata() {
+	t := suite.T()
+	secretInfo := suite.secretInfo
+	secretInfo.Project = "test-project"
+	secretInfo.Replication = "user-managed"
+	secretInfo.Locations = []string{"europe-west1", "us-east1"}
+	secretInfo.KmsKeys = []string{"projects/test-project/locations/global/keyRings/r/cryptoKeys/k"}
+	secretInfo.Etag = `"abc123"`
+	secretInfo.VersionDestroyTTL = 7 * 24 * time.Hour
+	modal := NewSecretInfoModal(secretInfo, suite.testSecret)
+	modal.SetVersions([]client.Version{{Version: 3, State: "DISABLED"}, {Version: 2, State: "ENABLED"}, {Version: 1, State: "ENABLED"}})
+
+	view := modal.View()
+
+	assert.Contains(t, view, "user-managed (europe-west1, us-east1)")
+	assert.Contains(t, view, "cryptoKeys/k")
+	assert.Contains(t, view, `Etag: "abc123"`)
+	assert.Contains(t, view, "Versions: 3 (latest enabled 2)")
+	assert.Contains(t, view, "Version Destroy TTL: 7d")
+	assert.Contains(t, view, secretInfo.ConsoleURL())
+}
+
+func (suite *SecretInfoModalTestSuite) TestViewVersionReplication() {
+	t := suite.T()
+	versionSecret := NewSecret("2", "projects/test-project/secrets/test-secret", "version", 2, time.Now())
+	modal := NewSecretInfoModal(suite.secretInfo, versionSecret)
+	modal.SetVersions([]client.Version{
+		{Version: 2, State: "ENABLED", Replicas: []client.Replica{{Location: "europe-west1", KmsKeyVersion: "key/cryptoKeyVersions/4"}, {Location: "us-east1"}}},
+	})
+
+	view := modal.View()
+
+	assert.Contains(t, view, "Replicas:")
+	assert.Contains(t, view, "europe-west1: key/cryptoKeyVersions/4")
+	assert.Contains(t, view, "us-east1")
+
+	modal.SetVersions([]client.Version{{Version: 2, State: "ENABLED", Replicas: []client.Replica{{}}}})
+	assert.Contains(t, modal.View(), "Replication: automatic")
+}
+
+func (suite *SecretInfoModalTestSuite) TestSummary() {
+	t := suite.T()
+	versions := []client.Version{
+		{Version: 2, State: "ENABLED", CreatedAt: time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC), Aliases: []string{"prod"}},
+		{Version: 1, State: "DISABLED", CreatedAt: time.Date(2024, 1, 15, 12, 30, 0, 0, time.UTC)},
+	}
+	suite.modal.SetVersions(versions)
+
+	summary := suite.modal.Summary("o")
+
+	assert.Contains(t, summary, "test-secret")
+	assert.Contains(t, summary, "environment: test")
+	assert.Contains(t, summary, "Versions (2):")
+	assert.Contains(t, summary, "2    enabled   2024-02-01 09:00 @prod")
+	assert.Contains(t, summary, "1    disabled  2024-01-15 12:30")
+	assert.Contains(t, summary, "Press o to fetch the value")
+	assert.NotContains(t, summary, "Press ESC to close")
+}
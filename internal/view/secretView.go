@@ -3,6 +3,7 @@ package view
 import (
 	"strings"
 
+	"github.com/acarl005/stripansi"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -16,6 +17,7 @@ type SecretView struct {
 	FilterValue string
 	isFiltered  bool
 	content     string
+	highlighted map[int]bool
 }
 
 func NewSecretView(width, height int) SecretView {
@@ -28,6 +30,16 @@ func NewSecretView(width, height int) SecretView {
 
 func (s *SecretView) SetContent(content string) {
 	s.content = content
+	s.highlighted = nil
+	s.teaView.SetContent(s.HighlightText())
+}
+
+// SetHighlightLines marks the lines of the content matched by a deep search.
+func (s *SecretView) SetHighlightLines(lines []int) {
+	s.highlighted = make(map[int]bool, len(lines))
+	for _, line := range lines {
+		s.highlighted[line] = true
+	}
 	s.teaView.SetContent(s.HighlightText())
 }
 
@@ -116,11 +128,22 @@ func (s *SecretView) ToggleFocus() {
 }
 
 func (s *SecretView) HighlightText() string {
+	content := s.content
+	if len(s.highlighted) > 0 {
+		lines := strings.Split(content, "\n")
+		for i, line := range lines {
+			if s.highlighted[i] {
+				lines[i] = "\033[1;37;41m" + stripansi.Strip(line) + "\033[0m"
+			}
+		}
+		content = strings.Join(lines, "\n")
+	}
+
 	if s.FilterValue == "" || !s.isFiltered {
-		return s.content
+		return content
 	}
 
-	return strings.ReplaceAll(s.content, s.FilterValue, "\033[1;37;41m"+s.FilterValue+"\033[0m")
+	return strings.ReplaceAll(content, s.FilterValue, "\033[1;37;41m"+s.FilterValue+"\033[0m")
 }
 
 func (s *SecretView) SetFilteredValue(value string) {
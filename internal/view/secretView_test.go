@@ -141,6 +141,27 @@ func (suite *SecretViewTestSuite) TestHighlightText_WithFilter() {
 	assert.Contains(t, result, "content")
 }
 
+func (suite *SecretViewTestSuite) TestHighlightText_WithHighlightLines() {
+	t := suite.T()
+	suite.secretView.SetContent("FIRST=1\nREDIS_URL=redis://\nLAST=3")
+
+	suite.secretView.SetHighlightLines([]int{1})
+
+	result := suite.secretView.HighlightText()
+	assert.Contains(t, result, "\033[1;37;41mREDIS_URL=redis://\033[0m")
+	assert.NotContains(t, result, "\033[1;37;41mFIRST=1")
+}
+
+func (suite *SecretViewTestSuite) TestSetContent_ClearsHighlightLines() {
+	t := suite.T()
+	suite.secretView.SetContent("KEY=value")
+	suite.secretView.SetHighlightLines([]int{0})
+
+	suite.secretView.SetContent("KEY=other")
+
+	assert.Equal(t, "KEY=other", suite.secretView.HighlightText())
+}
+
 func (suite *SecretViewTestSuite) TestUpdate_StartFiltering() {
 	t := suite.T()
 	suite.secretView.IsFocused = true
@@ -10,6 +10,8 @@ import (
 	client "smm/internal/client"
 	"smm/internal/ui"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -22,6 +24,11 @@ type Secret struct {
 	version     int
 	related     *Secret
 	createdAt   time.Time
+	project     string
+	location    string
+	aliases     string
+	rotationDue bool
+	expireTime  time.Time
 }
 
 type ResizeMessage struct{}
@@ -73,6 +80,55 @@ func (t *Secret) SetRelated(secret *Secret) {
 	t.related = secret
 }
 
+// Project returns the project the secret was loaded from.
+func (t Secret) Project() string {
+	return t.project
+}
+
+func (t *Secret) SetProject(project string) {
+	t.project = project
+}
+
+// Aliases returns the version aliases pointing to the version shown by the
+// item. They are kept joined so that items stay comparable.
+func (t Secret) Aliases() []string {
+	if t.aliases == "" {
+		return nil
+	}
+	return strings.Split(t.aliases, ",")
+}
+
+func (t *Secret) SetAliases(aliases []string) {
+	t.aliases = strings.Join(aliases, ",")
+}
+
+// RotationDue reports whether the next rotation of the secret has passed.
+func (t Secret) RotationDue() bool {
+	return t.rotationDue
+}
+
+func (t *Secret) SetRotationDue(due bool) {
+	t.rotationDue = due
+}
+
+// Location is the region of a regional secret, empty for global ones.
+func (t Secret) Location() string {
+	return t.location
+}
+
+func (t *Secret) SetLocation(location string) {
+	t.location = location
+}
+
+// ExpireTime is when the secret will be deleted, zero if it never expires.
+func (t Secret) ExpireTime() time.Time {
+	return t.expireTime
+}
+
+func (t *Secret) SetExpireTime(expireTime time.Time) {
+	t.expireTime = expireTime
+}
+
 func (t Secret) Hash() string {
 	hasher := sha256.New()
 	hasher.Write([]byte(t.title))
@@ -85,8 +141,12 @@ func (t *Secret) SetIndex(index int) {
 
 type SecretsList struct {
 	teaView     list.Model
+	delegate    *ItemDelegate
 	IsFocused   bool
 	SearchQuery string
+	searching   bool
+	scanned     int
+	total       int
 }
 
 func NewSecretsList(width, height int, gcp client.Client) SecretsList {
@@ -104,21 +164,36 @@ func NewSecretsList(width, height int, gcp client.Client) SecretsList {
 	myList.DisableQuitKeybindings()
 	myList.Filter = list.UnsortedFilter
 
+	secretsList := SecretsList{teaView: myList, delegate: dl, IsFocused: true}
+	if gcp != nil {
+		secretsList.LoadSecrets(gcp)
+	}
+
+	return secretsList
+}
+
+// LoadSecrets replaces the list content with every secret of the project.
+func (sl *SecretsList) LoadSecrets(gcp client.Client) {
 	var secretList []list.Item
 
-	if gcp != nil {
-		secretInfos, err := gcp.Secrets()
-		if err != nil {
-			log.Error().Err(err).Msg("failed to fetch secrets")
-		} else {
-			for _, secretInfo := range secretInfos {
-				secretList = append(secretList, NewSecret(secretInfo.Name, secretInfo.FullPath, "current", 0, secretInfo.CreateTime))
-			}
+	secretInfos, err := gcp.Secrets()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch secrets")
+	} else {
+		for _, secretInfo := range secretInfos {
+			secret := NewSecret(secretInfo.Name, secretInfo.FullPath, "current", 0, secretInfo.CreateTime)
+			secret.SetProject(secretInfo.Project)
+			secret.SetLocation(secretInfo.Location)
+			secret.SetRotationDue(secretInfo.RotationDue(time.Now()))
+			secret.SetExpireTime(secretInfo.ExpireTime)
+			secretList = append(secretList, secret)
 		}
 	}
 
-	myList.SetItems(secretList)
-	return SecretsList{teaView: myList, IsFocused: true}
+	sl.SearchQuery = ""
+	sl.delegate.ShowProjects = false
+	sl.ClearMarks()
+	sl.teaView.SetItems(secretList)
 }
 
 func (sl *SecretsList) SelectedItem() Secret {
@@ -195,6 +270,100 @@ func (sl *SecretsList) SelectByName(name string) {
 	}
 }
 
+// SetAliases replaces the aliases of the item at index.
+func (sl *SecretsList) SetAliases(index int, aliases []string) tea.Cmd {
+	items := sl.teaView.Items()
+	if index < 0 || index >= len(items) {
+		return nil
+	}
+
+	secret := items[index].(Secret)
+	secret.SetAliases(aliases)
+	return sl.teaView.SetItem(index, secret)
+}
+
+// SetSettings updates the rotation and expiration markers of the secret row
+// at fullPath.
+func (sl *SecretsList) SetSettings(fullPath string, settings client.SecretSettings) tea.Cmd {
+	for i, item := range sl.teaView.Items() {
+		secret := item.(Secret)
+		if secret.Type() == "current" && secret.FullPath() == fullPath {
+			secret.SetRotationDue(client.SecretInfo{NextRotationTime: settings.NextRotationTime}.RotationDue(time.Now()))
+			secret.SetExpireTime(settings.ExpireTime)
+			return sl.teaView.SetItem(i, secret)
+		}
+	}
+	return nil
+}
+
+// SelectPath selects the secret row of the secret at fullPath, returning
+// false if it is not listed.
+func (sl *SecretsList) SelectPath(fullPath string) bool {
+	for i, item := range sl.teaView.Items() {
+		secret := item.(Secret)
+		if secret.Type() == "current" && secret.FullPath() == fullPath {
+			sl.teaView.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
+// SelectVersion selects the version row of the secret at fullPath, returning
+// false if it is not listed.
+func (sl *SecretsList) SelectVersion(fullPath string, version int) bool {
+	for i, item := range sl.teaView.Items() {
+		secret := item.(Secret)
+		if secret.Type() == "version" && secret.FullPath() == fullPath && secret.Version() == version {
+			sl.teaView.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
+// MarkKey identifies a row, a secret or one of its versions, among the
+// marked ones.
+func MarkKey(secret Secret) string {
+	return secret.FullPath() + "@" + strconv.Itoa(secret.Version())
+}
+
+// ToggleMark marks the selected row or, if it was marked, unmarks it,
+// reporting whether it is now marked.
+func (sl *SecretsList) ToggleMark() bool {
+	selected := sl.SelectedItem()
+	if selected.FullPath() == "" {
+		return false
+	}
+	key := MarkKey(selected)
+	if sl.delegate.Marked[key] {
+		delete(sl.delegate.Marked, key)
+		return false
+	}
+	sl.delegate.Marked[key] = true
+	return true
+}
+
+// Marked returns the marked rows in the order they are listed.
+func (sl *SecretsList) Marked() []Secret {
+	var marked []Secret
+	for _, item := range sl.teaView.Items() {
+		if secret := item.(Secret); sl.delegate.Marked[MarkKey(secret)] {
+			marked = append(marked, secret)
+		}
+	}
+	return marked
+}
+
+func (sl *SecretsList) ClearMarks() {
+	clear(sl.delegate.Marked)
+}
+
+// SetExpiryWarning highlights secrets that expire within window.
+func (sl *SecretsList) SetExpiryWarning(window time.Duration) {
+	sl.delegate.ExpiryWarning = window
+}
+
 func (sl *SecretsList) Update(msg tea.Msg) (SecretsList, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
@@ -244,6 +413,8 @@ func (sl *SecretsList) DelVersionItems() bool {
 	for i, item := range items {
 		if item != nil && item.(Secret).Type() == "version" {
 			indexToDelete = append(indexToDelete, i)
+		} else if item != nil && item.(Secret).aliases != "" {
+			sl.SetAliases(i, nil)
 		}
 	}
 
@@ -260,33 +431,65 @@ func (sl *SecretsList) ResetFilter() {
 	sl.teaView.ResetFilter()
 }
 
-func (sl *SecretsList) DeepSearch(query string, gcp client.Client) {
+// StartSearch empties the list so that the results of a deep search for
+// query can be streamed into it. Results of a search spanning several
+// projects are grouped and labelled by project.
+func (sl *SecretsList) StartSearch(query string, multiProject bool) {
+	sl.SearchQuery = query
+	sl.delegate.ShowProjects = multiProject
+	sl.searching = true
+	sl.scanned = 0
+	sl.total = 0
+	sl.teaView.ResetFilter()
+	sl.teaView.SetItems([]list.Item{})
+}
 
-	var secretList []list.Item
+// AddSearchResult inserts a matching secret keeping the results sorted by
+// project and name, whatever order the workers found them in. Matching
+// versions are inserted as version rows under the secret.
+func (sl *SecretsList) AddSearchResult(secretInfo client.SecretInfo, versions []client.Version) tea.Cmd {
+	secret := NewSecret(secretInfo.Name, secretInfo.FullPath, "current", 0, secretInfo.CreateTime)
+	secret.SetProject(secretInfo.Project)
+	secret.SetLocation(secretInfo.Location)
+	secret.SetRotationDue(secretInfo.RotationDue(time.Now()))
+	secret.SetExpireTime(secretInfo.ExpireTime)
 
-	if query == "" {
-		secretInfos, err := gcp.Secrets()
-		if err != nil {
-			log.Error().Err(err).Msg("failed to fetch secrets")
-			return
-		}
-		for _, secretInfo := range secretInfos {
-			secretList = append(secretList, NewSecret(secretInfo.Name, secretInfo.FullPath, "current", 0, secretInfo.CreateTime))
-		}
-	} else {
-		searchResults, err := gcp.SearchInSecrets(query)
-		if err != nil {
-			log.Error().Err(err).Msg("failed to search secrets")
-			return
-		}
-		for _, secretInfo := range searchResults {
-			secretList = append(secretList, NewSecret(secretInfo.Name, secretInfo.FullPath, "current", 0, secretInfo.CreateTime))
-		}
+	items := sl.teaView.Items()
+	index := sort.Search(len(items), func(i int) bool {
+		return searchOrder(items[i].(Secret)) > searchOrder(secret)
+	})
+
+	cmds := []tea.Cmd{sl.teaView.InsertItem(index, secret)}
+	for i, version := range versions {
+		versionItem := NewSecret(strconv.Itoa(version.Version), version.FullPath, "version", version.Version, version.CreatedAt)
+		versionItem.SetRelated(&secret)
+		versionItem.SetProject(secretInfo.Project)
+		versionItem.SetAliases(version.Aliases)
+		cmds = append(cmds, sl.teaView.InsertItem(index+1+i, versionItem))
 	}
 
-	sl.SearchQuery = query
+	return tea.Batch(cmds...)
+}
 
-	sl.teaView.SetItems(secretList)
+func searchOrder(secret Secret) string {
+	return secret.Project() + "\x00" + secret.FilterValue()
+}
+
+func (sl *SecretsList) SetSearchProgress(scanned, total int) {
+	sl.scanned = scanned
+	sl.total = total
+}
+
+func (sl *SecretsList) FinishSearch() {
+	sl.searching = false
+}
+
+func (sl *SecretsList) IsSearching() bool {
+	return sl.searching
+}
+
+func (sl *SecretsList) SearchProgress() (int, int) {
+	return sl.scanned, sl.total
 }
 
 func (sl *SecretsList) ToggleFocus() {
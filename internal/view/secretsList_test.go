@@ -283,17 +283,110 @@ func (suite *SecretsListTestSuite) TestDelVersionItems_NoVersions() {
 	assert.False(t, deleted)
 }
 
-func (suite *SecretsListTestSuite) TestDeepSearch() {
+func (suite *SecretsListTestSuite) TestStartSearch() {
 	t := suite.T()
-	// FakeClient will return predefined results for search
 
-	// Test with query
-	suite.secretsList.DeepSearch("query", suite.mockClient)
+	suite.secretsList.StartSearch("query", false)
+
 	assert.Equal(t, "query", suite.secretsList.SearchQuery)
+	assert.True(t, suite.secretsList.IsSearching())
+	assert.Empty(t, suite.secretsList.teaView.Items())
+
+	suite.secretsList.FinishSearch()
+	assert.False(t, suite.secretsList.IsSearching())
+}
+
+func (suite *SecretsListTestSuite) TestAddSearchResult_KeepsResultsSorted() {
+	t := suite.T()
+	suite.secretsList.StartSearch("query", false)
+
+	suite.secretsList.AddSearchResult(client.SecretInfo{Name: "charlie", FullPath: "projects/p/secrets/charlie"}, nil)
+	suite.secretsList.AddSearchResult(client.SecretInfo{Name: "alpha", FullPath: "projects/p/secrets/alpha"}, nil)
+	suite.secretsList.AddSearchResult(client.SecretInfo{Name: "bravo", FullPath: "projects/p/secrets/bravo"}, nil)
+
+	items := suite.secretsList.teaView.Items()
+	assert.Len(t, items, 3)
+	assert.Equal(t, "alpha", items[0].(Secret).Title())
+	assert.Equal(t, "bravo", items[1].(Secret).Title())
+	assert.Equal(t, "charlie", items[2].(Secret).Title())
+}
+
+func (suite *SecretsListTestSuite) TestAddSearchResult_WithVersions() {
+	t := suite.T()
+	suite.secretsList.StartSearch("query", false)
+	createdAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	versions := []client.Version{
+		{Version: 4, FullPath: "projects/p/secrets/bravo", CreatedAt: createdAt},
+		{Version: 2, FullPath: "projects/p/secrets/bravo", CreatedAt: createdAt},
+	}
+
+	suite.secretsList.AddSearchResult(client.SecretInfo{Name: "bravo", FullPath: "projects/p/secrets/bravo"}, versions)
+	suite.secretsList.AddSearchResult(client.SecretInfo{Name: "alpha", FullPath: "projects/p/secrets/alpha"}, nil)
+	suite.secretsList.AddSearchResult(client.SecretInfo{Name: "charlie", FullPath: "projects/p/secrets/charlie"}, nil)
+
+	items := suite.secretsList.teaView.Items()
+	assert.Len(t, items, 5)
+	assert.Equal(t, "alpha", items[0].(Secret).Title())
+	assert.Equal(t, "bravo", items[1].(Secret).Title())
+	assert.Equal(t, "version", items[2].(Secret).Type())
+	assert.Equal(t, 4, items[2].(Secret).Version())
+	assert.Equal(t, "bravo", items[2].(Secret).Related().Title())
+	assert.Equal(t, 2, items[3].(Secret).Version())
+	assert.Equal(t, "charlie", items[4].(Secret).Title())
+}
+
+func (suite *SecretsListTestSuite) TestAddSearchResult_GroupsByProject() {
+	t := suite.T()
+	suite.secretsList.StartSearch("query", true)
+
+	suite.secretsList.AddSearchResult(client.SecretInfo{Project: "beta", Name: "alpha", FullPath: "projects/beta/secrets/alpha"}, nil)
+	suite.secretsList.AddSearchResult(client.SecretInfo{Project: "alpha", Name: "bravo", FullPath: "projects/alpha/secrets/bravo"}, nil)
+
+	items := suite.secretsList.teaView.Items()
+	assert.Len(t, items, 2)
+	assert.Equal(t, "alpha", items[0].(Secret).Project())
+	assert.Equal(t, "beta", items[1].(Secret).Project())
+	assert.True(t, suite.secretsList.delegate.ShowProjects)
+}
+
+func (suite *SecretsListTestSuite) TestSelectVersionAndAliases() {
+	t := suite.T()
+	suite.secretsList.StartSearch("query", false)
+	versions := []client.Version{{Version: 3, FullPath: "projects/p/secrets/bravo", Aliases: []string{"prod"}}}
+	suite.secretsList.AddSearchResult(client.SecretInfo{Name: "bravo", FullPath: "projects/p/secrets/bravo"}, versions)
+
+	assert.True(t, suite.secretsList.SelectVersion("projects/p/secrets/bravo", 3))
+	assert.Equal(t, []string{"prod"}, suite.secretsList.SelectedItem().Aliases())
+	assert.False(t, suite.secretsList.SelectVersion("projects/p/secrets/bravo", 2))
+
+	assert.True(t, suite.secretsList.SelectPath("projects/p/secrets/bravo"))
+	suite.secretsList.SetAliases(0, []string{"stable"})
+	assert.Equal(t, []string{"stable"}, suite.secretsList.SelectedItem().Aliases())
+
+	suite.secretsList.DelVersionItems()
+	assert.Len(t, suite.secretsList.teaView.Items(), 1)
+	assert.Empty(t, suite.secretsList.SelectedItem().Aliases())
+}
+
+func (suite *SecretsListTestSuite) TestSetSearchProgress() {
+	t := suite.T()
+	suite.secretsList.StartSearch("query", false)
+
+	suite.secretsList.SetSearchProgress(12, 30)
+
+	scanned, total := suite.secretsList.SearchProgress()
+	assert.Equal(t, 12, scanned)
+	assert.Equal(t, 30, total)
+}
+
+func (suite *SecretsListTestSuite) TestLoadSecrets() {
+	t := suite.T()
+	suite.secretsList.StartSearch("query", false)
+
+	suite.secretsList.LoadSecrets(suite.mockClient)
 
-	// Test with empty query
-	suite.secretsList.DeepSearch("", suite.mockClient)
 	assert.Empty(t, suite.secretsList.SearchQuery)
+	assert.Len(t, suite.secretsList.teaView.Items(), 30)
 }
 
 func (suite *SecretsListTestSuite) TestToggleFocus() {
@@ -352,4 +445,25 @@ func (suite *SecretsListTestSuite) TestInit() {
 	cmd := suite.secretsList.Init()
 
 	assert.Nil(t, cmd)
-}
\ No newline at end of file
+}
+
+func (suite *SecretsListTestSuite) TestToggleMark() {
+	t := suite.T()
+	first := suite.secretsList.SelectedItem()
+
+	assert.True(t, suite.secretsList.ToggleMark())
+	suite.secretsList.Select(1)
+	assert.True(t, suite.secretsList.ToggleMark())
+	second := suite.secretsList.SelectedItem()
+
+	marked := suite.secretsList.Marked()
+	assert.Len(t, marked, 2)
+	assert.Equal(t, first.FullPath(), marked[0].FullPath())
+	assert.Equal(t, second.FullPath(), marked[1].FullPath())
+
+	assert.False(t, suite.secretsList.ToggleMark())
+	assert.Len(t, suite.secretsList.Marked(), 1)
+
+	suite.secretsList.LoadSecrets(suite.mockClient)
+	assert.Empty(t, suite.secretsList.Marked())
+}